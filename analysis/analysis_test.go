@@ -0,0 +1,444 @@
+package analysis
+
+import (
+	"cryptochev/classical"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var english = []rune("IT WAS THE BEST OF TIMES, IT WAS THE WORST OF TIMES, IT WAS THE AGE OF WISDOM, IT WAS THE AGE OF FOOLISHNESS, IT WAS THE EPOCH OF BELIEF, IT WAS THE EPOCH OF INCREDULITY")
+
+var englishLong = []rune("IT WAS THE BEST OF TIMES, IT WAS THE WORST OF TIMES, IT WAS THE AGE OF WISDOM, IT WAS THE AGE OF FOOLISHNESS, " +
+	"IT WAS THE EPOCH OF BELIEF, IT WAS THE EPOCH OF INCREDULITY, IT WAS THE SEASON OF LIGHT, IT WAS THE SEASON OF DARKNESS, " +
+	"IT WAS THE SPRING OF HOPE, IT WAS THE WINTER OF DESPAIR, WE HAD EVERYTHING BEFORE US, WE HAD NOTHING BEFORE US, " +
+	"WE WERE ALL GOING DIRECT TO HEAVEN, WE WERE ALL GOING DIRECT THE OTHER WAY. THERE WERE A KING WITH A LARGE JAW AND A " +
+	"QUEEN WITH A PLAIN FACE, ON THE THRONE OF ENGLAND; THERE WERE A KING WITH A LARGE JAW AND A QUEEN WITH A FAIR FACE, " +
+	"ON THE THRONE OF FRANCE.")
+
+func TestAnalysis(t *testing.T) {
+	t.Run("TestCounts", testCounts)
+	t.Run("TestNGrams", testNGrams)
+	t.Run("TestStatistics", testStatistics)
+	t.Run("TestReport", testReport)
+	t.Run("TestBreakShift", testBreakShift)
+	t.Run("TestPeriods", testPeriods)
+	t.Run("TestSolveVigenere", testSolveVigenere)
+	t.Run("TestNGramModel", testNGramModel)
+	t.Run("TestSolveSubstitute", testSolveSubstitute)
+	t.Run("TestSolveTransposition", testSolveTransposition)
+}
+
+func near(a, b float64) bool { return math.Abs(a - b) < 1e-9 }
+
+func testCounts(t *testing.T) {
+	alphabet := []rune(classical.AlphabetL)
+
+	if filtered := string(Filter([]rune("WE ARE 12 DISCOVERED!"), alphabet)); filtered != "WEAREDISCOVERED" {
+		t.Errorf("Filter gave %s, expected WEAREDISCOVERED", filtered)
+	}
+
+	counts := Counts([]rune("ABRACADABRA"), []rune("ABCDR"))
+	if !reflect.DeepEqual(counts, []int{5, 2, 1, 1, 2}) {
+		t.Errorf("Counts gave %v", counts)
+	}
+
+	frequencies := Frequencies([]rune("AABJ"), []rune(classical.AlphabetL25))
+	if !near(frequencies['A'], 2.0 / 3) || !near(frequencies['B'], 1.0 / 3) || frequencies['Z'] != 0 || len(frequencies) != 25 {
+		t.Errorf("Frequencies gave %v", frequencies)
+	}
+
+	if frequencies := Frequencies(nil, alphabet); frequencies['A'] != 0 {
+		t.Errorf("Frequencies of an empty text gave %v", frequencies)
+	}
+}
+
+func testNGrams(t *testing.T) {
+	alphabet := []rune(classical.AlphabetL36)
+
+	bigrams := Bigrams([]rune("AB AB-1"), alphabet)
+	if !reflect.DeepEqual(bigrams, map[string]int{"AB": 2, "BA": 1, "B1": 1}) {
+		t.Errorf("Bigrams gave %v", bigrams)
+	}
+
+	if trigrams := Trigrams([]rune("AB"), alphabet); len(trigrams) != 0 {
+		t.Errorf("Trigrams of a short text gave %v", trigrams)
+	}
+
+	quadgrams := Quadgrams([]rune("AAAAA"), alphabet)
+	if quadgrams["AAAA"] != 2 || len(quadgrams) != 1 {
+		t.Errorf("Quadgrams gave %v", quadgrams)
+	}
+
+	top := Top(Unigrams([]rune("ABRACADABRA"), alphabet), 3)
+	if !reflect.DeepEqual(top, []NGram{{"A", 5}, {"B", 2}, {"R", 2}}) {
+		t.Errorf("Top gave %v", top)
+	}
+
+	if trigrams := Trigrams(english, alphabet); Top(trigrams, 1)[0].Count != trigrams["THE"] {
+		t.Errorf("THE is not among the most frequent trigrams: %v", Top(trigrams, 10))
+	}
+
+	if all := Top(map[string]int{"X": 1, "Y": 1}, -1); len(all) != 2 {
+		t.Errorf("Top -1 gave %v", all)
+	}
+}
+
+func testStatistics(t *testing.T) {
+	alphabet := []rune(classical.AlphabetL)
+
+	// 4 pairs of A among the 4 × 3 ordered pairs
+	if ioc := IndexOfCoincidence([]rune("AABB"), alphabet); !near(ioc, 4.0 / 12) {
+		t.Errorf("Index of coincidence of AABB is %f", ioc)
+	}
+
+	if ioc := IndexOfCoincidence([]rune("A"), alphabet); ioc != 0 {
+		t.Errorf("Index of coincidence of A is %f", ioc)
+	}
+
+	if ioc := LanguageIndexOfCoincidence(English); ioc < 0.064 || ioc > 0.068 {
+		t.Errorf("Index of coincidence of English is %f", ioc)
+	}
+
+	if ioc := IndexOfCoincidence(english, alphabet); ioc < 0.055 {
+		t.Errorf("Index of coincidence of English text is %f", ioc)
+	}
+
+	// A substitution keeps the index of coincidence, a Vigenere brings it down towards random.
+	substituted, _ := classical.EncryptSubstitute(classical.NewKeySubstitute(alphabet, []rune("QWERTYUIOPASDFGHJKLZXCVBNM")), Filter(english, alphabet))
+	if a, b := IndexOfCoincidence(english, alphabet), IndexOfCoincidence(substituted, alphabet); !near(a, b) {
+		t.Errorf("Substitution changed the index of coincidence from %f to %f", a, b)
+	}
+
+	vigenere, _ := classical.EncryptVigenere(classical.NewKeyVigenere(alphabet, []rune("LEMONADE")), Filter(english, alphabet))
+	if ioc := IndexOfCoincidence(vigenere, alphabet); ioc > IndexOfCoincidence(english, alphabet) {
+		t.Errorf("Vigenere raised the index of coincidence to %f", ioc)
+	}
+
+	if chi := ChiSquared(english, alphabet, English); chi > ChiSquared(substituted, alphabet, English) {
+		t.Errorf("English text fits English worse than its substitution: %f", chi)
+	}
+
+	// Expected counts of 1 and 3 give (2 - 1)² / 1 + (2 - 3)² / 3.
+	if chi := ChiSquared([]rune("AABB"), []rune("AB"), map[rune]float64{'A': 0.25, 'B': 0.75}); !near(chi, 1 + 1.0 / 3) {
+		t.Errorf("Chi-squared of AABB is %f", chi)
+	}
+
+	// J is missing from AlphabetL25, the others are scaled up.
+	if chi := ChiSquared([]rune("AB"), []rune("AB"), map[rune]float64{'A': 0.25, 'B': 0.25, 'J': 0.5}); !near(chi, 0) {
+		t.Errorf("Chi-squared without J is %f", chi)
+	}
+
+	if chi := ChiSquared([]rune("123"), []rune(classical.AlphabetL36), English); chi != 0 {
+		t.Errorf("Chi-squared of digits is %f", chi)
+	}
+
+	if entropy := Entropy([]rune(classical.AlphabetL), alphabet); !near(entropy, math.Log2(26)) {
+		t.Errorf("Entropy of the alphabet is %f", entropy)
+	}
+
+	if entropy := Entropy([]rune("AAAA"), alphabet); entropy != 0 {
+		t.Errorf("Entropy of AAAA is %f", entropy)
+	}
+}
+
+func testReport(t *testing.T) {
+	c := classical.NewCaesar(Filter(english, []rune(classical.AlphabetL)), classical.NewKeyCaesar(3))
+	c.Encrypt()
+
+	report := AnalyzeCipher(c, []rune(classical.AlphabetL), English, 10)
+	if report.Length != len(c.GetText()) || report.Trigrams[0].Count != 6 {
+		t.Errorf("Report of a Caesar cipher gave %d runes and trigrams %v", report.Length, report.Trigrams)
+	}
+
+	s := report.String()
+	for _, line := range []string{"Length: ", "Index of coincidence: ", "Chi-squared: ", "Entropy: ", "  H ", " WKH 6 "} {
+		if !strings.Contains(s, line) {
+			t.Errorf("Report does not contain %q:\n%s", line, s)
+		}
+	}
+
+	if all := AnalyzeCipher(c, []rune(classical.AlphabetL), English, -1); len(report.Bigrams) != 10 || len(all.Bigrams) != len(Bigrams(c.GetText(), []rune(classical.AlphabetL))) {
+		t.Errorf("Report kept %d and %d bigrams", len(report.Bigrams), len(all.Bigrams))
+	}
+
+	if s := NewReport(nil, []rune("AB"), English, 10).String(); !strings.Contains(s, "  A     0   0.00% \n") {
+		t.Errorf("Report of an empty text is:\n%s", s)
+	}
+}
+
+func testBreakShift(t *testing.T) {
+	fitness := UnigramFitness(English)
+	alphabet := []rune(classical.AlphabetL)
+
+	caesar, _ := classical.EncryptCaesar(classical.NewKeyCaesar(7), english)
+	candidates := BreakCaesar(caesar, fitness, 3)
+	if len(candidates) != 3 || candidates[0].Key.Shift != 7 || string(candidates[0].Text) != string(english) {
+		t.Errorf("Caesar was broken as %d: %s", candidates[0].Key.Shift, string(candidates[0].Text))
+	}
+	if candidates[0].Score < candidates[1].Score || candidates[1].Score < candidates[2].Score {
+		t.Errorf("Caesar candidates are not ranked: %f %f %f", candidates[0].Score, candidates[1].Score, candidates[2].Score)
+	}
+
+	rot13, _ := classical.EncryptROT13([]rune("Why did the chicken cross the road? To get to the other side."))
+	if candidates := BreakCaesar(rot13, fitness, -1); len(candidates) != 26 || candidates[0].Key.Shift != 13 {
+		t.Errorf("ROT13 was broken as %d: %s", candidates[0].Key.Shift, string(candidates[0].Text))
+	}
+
+	l36 := []rune(classical.AlphabetL36)
+	plain := []rune("MEET ME AT 1200 AT THE USUAL PLACE AND BRING THE DOCUMENTS FROM THE EMBASSY")
+	shifted, _ := classical.EncryptShiftAlphabet(classical.NewKeyShiftAlphabet(l36, 30), Filter(plain, l36))
+	shiftCandidates, err := BreakShiftAlphabet(shifted, l36, fitness, 1)
+	if err != nil || shiftCandidates[0].Key.Shift != 30 {
+		t.Errorf("Shift alphabet was broken as %v: %v", shiftCandidates, err)
+	}
+
+	affine, _ := classical.EncryptAffine(classical.NewKeyAffine(alphabet, 5, 8), Filter(english, alphabet))
+	spaced := []rune(classical.ToSpaced(string(affine), 5))
+	affineCandidates, err := BreakAffine(spaced, alphabet, fitness, 5)
+	if err != nil {
+		t.Fatalf("Affine was not broken: %v", err)
+	}
+	if key := affineCandidates[0].Key; key.A != 5 || key.B != 8 || string(Filter(affineCandidates[0].Text, alphabet)) != string(Filter(english, alphabet)) {
+		t.Errorf("Affine was broken as %d, %d: %s", key.A, key.B, string(affineCandidates[0].Text))
+	}
+	if text := string(affineCandidates[0].Text); text[5] != ' ' {
+		t.Errorf("Affine candidate lost the spaces: %s", text)
+	}
+
+	if all, _ := BreakAffine(affine, l36, fitness, -1); len(all) != 12 * 36 {
+		t.Errorf("Affine over 36 runes tried %d keys", len(all))
+	}
+
+	if _, err := BreakAffine(affine, []rune("AAB"), fitness, 1); !errors.Is(err, classical.ErrInvalidKey) {
+		t.Errorf("Affine with an invalid alphabet gave %v", err)
+	}
+}
+
+func testPeriods(t *testing.T) {
+	alphabet := []rune(classical.AlphabetL)
+
+	if kasiski := Kasiski([]rune("ABCXXABCYYABC"), alphabet, 6); kasiski[5] != 2 || kasiski[2] != 0 {
+		t.Errorf("Kasiski gave %v", kasiski)
+	}
+
+	vigenere, _ := classical.EncryptVigenere(classical.NewKeyVigenere(alphabet, []rune("LEMON")), Filter(englishLong, alphabet))
+	if a, b := PeriodIndexOfCoincidence(vigenere, alphabet, 1), PeriodIndexOfCoincidence(vigenere, alphabet, 5); a >= b || b < 0.055 {
+		t.Errorf("Index of coincidence at period 5 is %f, and %f at period 1", b, a)
+	}
+
+	if friedman := Friedman(vigenere, alphabet, English); friedman < 2 || friedman > 10 {
+		t.Errorf("Friedman estimated the period at %f", friedman)
+	}
+
+	periods := EstimatePeriods(vigenere, alphabet, English, 20)
+	if periods[0].Length != 5 || len(periods) != 20 {
+		t.Errorf("Period was estimated as %v", periods[:3])
+	}
+
+	// Without a repeated rune every period is below the threshold and has no Kasiski count, so
+	// the Friedman estimate of the whole length orders them from the longest.
+	if periods := EstimatePeriods([]rune("QWERTYUIOPASDFGHJKLZXCVBNM"), alphabet, English, 20); periods[0].Length != 13 || periods[12].Length != 1 {
+		t.Errorf("Periods were not ordered by the Friedman estimate: %v", periods)
+	}
+
+	if periods := EstimatePeriods([]rune("ABCDEF"), alphabet, English, 20); len(periods) != 3 {
+		t.Errorf("A short text gave %d periods", len(periods))
+	}
+}
+
+func encryptHeld(c classical.ICipherClassical) string {
+	held := classical.NewPolicy(classical.PolicyPassthrough, true, true)
+	switch c := c.(type) {
+	case *classical.Vigenere:
+		c.Cipher.Policy = held
+	case *classical.VigenereBeaufort:
+		c.Cipher.Policy = held
+	case *classical.VigenereGronsfeld:
+		c.Cipher.Policy = held
+	case *classical.Beaufort:
+		c.Cipher.Policy = held
+	}
+	c.Encrypt()
+
+	return string(c.GetText())
+}
+
+func testSolveVigenere(t *testing.T) {
+	alphabet := []rune(classical.AlphabetL)
+	plain := string(englishLong)
+
+	vigenere := encryptHeld(classical.NewVigenere(englishLong, classical.NewKeyVigenere(alphabet, []rune("LEMON"))))
+	candidates := SolveVigenere([]rune(vigenere), nil)
+	if string(candidates[0].Key.Key) != "LEMON" || string(candidates[0].Text) != plain {
+		t.Errorf("Vigenere was solved as %s: %s", string(candidates[0].Key.Key), string(candidates[0].Text))
+	}
+
+	// The key found at 10 and 15 repeats LEMON, those candidates are dropped.
+	for _, c := range candidates[1:] {
+		if string(c.Key.Key) == "LEMON" {
+			t.Errorf("Vigenere candidate LEMON is repeated")
+		}
+	}
+
+	// Lowercase text keeps its case.
+	lower := encryptHeld(classical.NewVigenere([]rune(strings.ToLower(plain)), classical.NewKeyVigenere(alphabet, []rune("LEMON"))))
+	if candidates := SolveVigenere([]rune(lower), &VigenereOptions{Refine: true}); string(candidates[0].Text) != strings.ToLower(plain) {
+		t.Errorf("Lowercase Vigenere was solved as %s: %s", string(candidates[0].Key.Key), string(candidates[0].Text))
+	}
+
+	variant := encryptHeld(classical.NewVigenereBeaufort(englishLong, classical.NewKeyVigenere(alphabet, []rune("CIPHER"))))
+	if candidates := SolveVigenereBeaufort([]rune(variant), nil); string(candidates[0].Key.Key) != "CIPHER" || string(candidates[0].Text) != plain {
+		t.Errorf("Variant Beaufort was solved as %s", string(candidates[0].Key.Key))
+	}
+
+	gronsfeld := encryptHeld(classical.NewVigenereGronsfeld(englishLong, classical.NewKeyVigenere(alphabet, []rune("31415"))))
+	if candidates := SolveVigenereGronsfeld([]rune(gronsfeld), nil); string(candidates[0].Key.Key) != "31415" || string(candidates[0].Text) != plain {
+		t.Errorf("Gronsfeld was solved as %s", string(candidates[0].Key.Key))
+	}
+
+	beaufort := encryptHeld(classical.NewBeaufort(englishLong, classical.NewKeyBeaufort(alphabet, []rune("KEYWORD"))))
+	if candidates := SolveBeaufort([]rune(beaufort), &VigenereOptions{Periods: 1}); len(candidates) != 1 || string(candidates[0].Key.Key) != "KEYWORD" || string(candidates[0].Text) != plain {
+		t.Errorf("Beaufort was solved as %s", string(candidates[0].Key.Key))
+	}
+
+	// Quagmire III, the alphabet is keyed by one of the guessed keywords. The columns of 50 letters
+	// leave a key letter to the refinement.
+	keyed := classical.AlphabetKey(alphabet, []rune("KRYPTOS"))
+	quagmire := encryptHeld(classical.NewVigenere(englishLong, classical.NewKeyVigenere(keyed, []rune("PALIMPSEST"))))
+	options := &VigenereOptions{Refine: true, Keywords: [][]rune{[]rune("ZEBRA"), []rune("KRYPTOS"), []rune("ABSCISSA")}}
+	candidates = SolveVigenere([]rune(quagmire), options)
+	if string(candidates[0].Key.Alphabet) != string(keyed) || string(candidates[0].Key.Key) != "PALIMPSEST" || string(candidates[0].Text) != plain {
+		t.Errorf("Quagmire was solved as %s, %s", string(candidates[0].Key.Alphabet), string(candidates[0].Key.Key))
+	}
+}
+
+func testNGramModel(t *testing.T) {
+	counts, err := ParseNGrams(strings.NewReader("# comment\n\nAB 3\nBA 1\n"))
+	if err != nil || !reflect.DeepEqual(counts, map[string]int{"AB": 3, "BA": 1}) {
+		t.Errorf("ParseNGrams returned %v, %v", counts, err)
+	}
+
+	for _, data := range []string{"AB", "AB x", "AB -1"} {
+		if _, err := ParseNGrams(strings.NewReader(data)); err == nil {
+			t.Errorf("ParseNGrams accepted %q", data)
+		}
+	}
+
+	if _, err := NewNGramModel(map[string]int{"AB": 1, "ABC": 1}); err == nil {
+		t.Errorf("NewNGramModel accepted mixed lengths")
+	}
+	if _, err := NewNGramModel(map[string]int{}); err == nil {
+		t.Errorf("NewNGramModel accepted no n-grams")
+	}
+
+	model, _ := NewNGramModel(counts)
+	if model.N != 2 || string(model.Alphabet) != "AB" || !near(model.Score([]rune("a-b")), math.Log10(0.75)) || !near(model.Score([]rune("AA")), model.Floor) {
+		t.Errorf("NewNGramModel returned %v", model)
+	}
+
+	shuffled := classical.NewSeededRandom(1).Shuffle(append([]rune{}, englishLong...))
+	for _, m := range []*NGramModel{OpticksBigrams(), OpticksQuadgrams()} {
+		if m.Score(englishLong) <= m.Score(shuffled) {
+			t.Errorf("Opticks %d-grams prefer shuffled text", m.N)
+		}
+	}
+}
+
+func testSolveSubstitute(t *testing.T) {
+	alphabet := []rune(classical.AlphabetL)
+	key := classical.NewKeySubstitute(alphabet, []rune("QWERTYUIOPASDFGHJKLZXCVBNM"))
+	c := classical.NewSubstitute(append([]rune{}, englishLong...), key)
+	c.Cipher.Policy = passthrough
+	c.Encrypt()
+	substituted := c.GetText()
+
+	restarts := 0
+	options := &SubstituteOptions{Model: OpticksQuadgrams(), Random: classical.NewSeededRandom(7), Progress: func(int, Candidate[classical.KeySubstitute]) { restarts++ }}
+	candidates, err := SolveSubstitute(substituted, options)
+	if err != nil || len(candidates) != 10 || restarts != 10 {
+		t.Fatalf("SolveSubstitute returned %d candidates after %d restarts, %v", len(candidates), restarts, err)
+	}
+
+	// The quadgrams hardly know J, JAW is allowed to come out as XAW.
+	differ := 0
+	for i, r := range candidates[0].Text {
+		if r != englishLong[i] {
+			differ++
+		}
+	}
+	if differ > 2 {
+		t.Errorf("Substitute was solved as %s: %s", string(candidates[0].Key.SAlphabet), string(candidates[0].Text))
+	}
+
+	// The same seed gives the same candidates, climbing or annealing.
+	for _, temperature := range []float64{-1, 5} {
+		solve := func() []Candidate[classical.KeySubstitute] {
+			candidates, _ := SolveSubstitute(substituted, &SubstituteOptions{Model: OpticksQuadgrams(), Restarts: 2, Iterations: 3000, Temperature: temperature, Random: classical.NewSeededRandom(3)})
+			return candidates
+		}
+		if a, b := solve(), solve(); !reflect.DeepEqual(a, b) || a[0].Score < a[1].Score {
+			t.Errorf("Seeded substitute solver at %v is not deterministic", temperature)
+		}
+	}
+
+	if _, err := SolveSubstitute(substituted, nil); !errors.Is(err, ErrNoModel) {
+		t.Errorf("SolveSubstitute without a model returned %v", err)
+	}
+}
+
+func testSolveTransposition(t *testing.T) {
+	plain := Filter(englishLong, []rune(classical.AlphabetL))
+	if len(plain) % 6 == 0 || len(plain) % 10 == 0 {
+		t.Fatalf("The columns of the plain text are regular")
+	}
+
+	options := &TranspositionOptions{Model: OpticksBigrams(), MaxWidth: 10, Random: classical.NewSeededRandom(5)}
+	for _, key := range []string{"ZEBRAS", "CUMBERLAND"} {
+		column, _ := classical.EncryptColumn(classical.NewKeyColumn([]rune(key)), plain)
+		candidates, _ := SolveColumn(column, options)
+		if len(candidates) != 9 || len(candidates[0].Key.Key) != len(key) || string(candidates[0].Text) != string(plain) {
+			t.Errorf("Column %s was solved as %s: %s", key, string(candidates[0].Key.Key), string(candidates[0].Text))
+		}
+	}
+
+	// Lowercase text and punctuation are moved along.
+	lower := []rune(strings.ToLower(string(englishLong)))
+	column, _ := classical.EncryptColumn(classical.NewKeyColumn([]rune("ZEBRAS")), lower)
+	if candidates, _ := SolveColumn(column, options); string(candidates[0].Key.Key) != "FCBDAE" || string(candidates[0].Text) != string(lower) {
+		t.Errorf("Lowercase column was solved as %s: %s", string(candidates[0].Key.Key), string(candidates[0].Text))
+	}
+
+	// PROGRAMMER is wider than the keys tried exhaustively, its ranks are climbed to.
+	for _, c := range [][2]string{{"BANANA", "BACACA"}, {"PROGRAMMER", "FGECGADDBG"}} {
+		key, ranks := c[0], c[1]
+		myszkowski, _ := classical.EncryptMyszkowski(classical.NewKeyMyszkowski([]rune(key)), plain)
+		candidates, _ := SolveMyszkowski(myszkowski, options)
+		if string(candidates[0].Key.Key) != ranks || string(candidates[0].Text) != string(plain) {
+			t.Errorf("Myszkowski %s was solved as %s: %s", key, string(candidates[0].Key.Key), string(candidates[0].Text))
+		}
+	}
+
+	// Keys are lettered from A to Z, no wider key is tried.
+	wide := &TranspositionOptions{Model: OpticksBigrams(), MaxWidth: 40, Restarts: 1, Random: classical.NewSeededRandom(5)}
+	candidates, _ := SolveColumn(plain, wide)
+	if len(candidates) != 25 {
+		t.Errorf("Column up to 40 wide tried %d widths", len(candidates))
+	}
+	for _, candidate := range candidates {
+		for _, r := range candidate.Key.Key {
+			if r < 'A' || r > 'Z' {
+				t.Errorf("Column key %s is not lettered from A to Z", string(candidate.Key.Key))
+			}
+		}
+	}
+
+	if _, err := SolveColumn(plain, nil); !errors.Is(err, ErrNoModel) {
+		t.Errorf("SolveColumn without a model returned %v", err)
+	}
+	if _, err := SolveMyszkowski(plain, &TranspositionOptions{MaxWidth: 4}); !errors.Is(err, ErrNoModel) {
+		t.Errorf("SolveMyszkowski without a model returned %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+package analysis
+
+import (
+	"math"
+	"sort"
+	"unicode"
+)
+
+// A Fitness scores how close a text is to a language, higher is better.
+type Fitness func(text []rune) float64
+
+// Log frequency given to letters and digits missing from a language, below any English letter.
+var floor = math.Log10(0.0001)
+
+// UnigramFitness sums the log frequencies of the letters folded to upper case. Letters and digits
+// missing from the language score the floor, spaces and punctuation are skipped.
+func UnigramFitness(language map[rune]float64) Fitness {
+	return func(text []rune) float64 {
+		score := 0.0
+		for _, r := range text {
+			if p := language[unicode.ToUpper(r)]; p > 0 {
+				score += math.Log10(p)
+			} else if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				score += floor
+			}
+		}
+
+		return score
+	}
+}
+
+// A Candidate is a key tried by a solver with the text it decrypts to and its fitness.
+type Candidate[K any] struct {
+	Key *K
+	Text []rune
+	Score float64
+}
+
+// Ranks candidates by decreasing score and keeps the first n of them, a negative n keeps them all.
+func rank[K any](candidates []Candidate[K], n int) []Candidate[K] {
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Score > candidates[j].Score })
+
+	if n >= 0 && n < len(candidates) {
+		candidates = candidates[:n]
+	}
+
+	return candidates
+}
@@ -0,0 +1,105 @@
+package analysis
+
+import "sort"
+
+// Filter keeps the runes of the alphabet, n-grams are then counted across the dropped runes.
+func Filter(text, alphabet []rune) []rune {
+	amap := buildIndexMap(alphabet)
+	result := make([]rune, 0, len(text))
+	for _, r := range text {
+		if _, found := amap[r]; found {
+			result = append(result, r)
+		}
+	}
+
+	return result
+}
+
+// Counts gives the number of times each rune of the alphabet appears, in the alphabet order.
+func Counts(text, alphabet []rune) []int {
+	amap := buildIndexMap(alphabet)
+	counts := make([]int, len(alphabet))
+	for _, r := range text {
+		if i, found := amap[r]; found {
+			counts[i]++
+		}
+	}
+
+	return counts
+}
+
+func Frequencies(text, alphabet []rune) map[rune]float64 {
+	counts := Counts(text, alphabet)
+	total := sum(counts)
+
+	frequencies := make(map[rune]float64, len(alphabet))
+	for i, r := range alphabet {
+		if total > 0 {
+			frequencies[r] = float64(counts[i]) / float64(total)
+		} else {
+			frequencies[r] = 0
+		}
+	}
+
+	return frequencies
+}
+
+func NGrams(text, alphabet []rune, n int) map[string]int {
+	filtered := Filter(text, alphabet)
+	counts := make(map[string]int)
+	for i := 0; i + n <= len(filtered); i++ {
+		counts[string(filtered[i:i + n])]++
+	}
+
+	return counts
+}
+
+func Unigrams(text, alphabet []rune) map[string]int { return NGrams(text, alphabet, 1) }
+func Bigrams(text, alphabet []rune) map[string]int { return NGrams(text, alphabet, 2) }
+func Trigrams(text, alphabet []rune) map[string]int { return NGrams(text, alphabet, 3) }
+func Quadgrams(text, alphabet []rune) map[string]int { return NGrams(text, alphabet, 4) }
+
+type NGram struct {
+	Gram string
+	Count int
+}
+
+// Top sorts n-grams by decreasing count then alphabetically, and keeps the first n of them. A
+// negative n keeps them all.
+func Top(counts map[string]int, n int) []NGram {
+	grams := make([]NGram, 0, len(counts))
+	for gram, count := range counts {
+		grams = append(grams, NGram{gram, count})
+	}
+
+	sort.Slice(grams, func(i, j int) bool {
+		if grams[i].Count != grams[j].Count {
+			return grams[i].Count > grams[j].Count
+		}
+		return grams[i].Gram < grams[j].Gram
+	})
+
+	if n >= 0 && n < len(grams) {
+		grams = grams[:n]
+	}
+
+	return grams
+}
+
+func buildIndexMap(alphabet []rune) map[rune]int {
+	amap := make(map[rune]int, len(alphabet))
+	for i, r := range alphabet {
+		amap[r] = i
+	}
+
+	return amap
+}
+
+func sum(counts []int) int {
+	total := 0
+	for _, c := range counts {
+		total += c
+	}
+
+	return total
+}
@@ -0,0 +1,27 @@
+package analysis
+
+// Letter frequencies of English and French texts over AlphabetL. The French ones leave out the
+// accented letters and sum to a little less than 1.
+var English = map[rune]float64{
+	'A': 0.08167, 'B': 0.01492, 'C': 0.02782, 'D': 0.04253, 'E': 0.12702, 'F': 0.02228, 'G': 0.02015,
+	'H': 0.06094, 'I': 0.06966, 'J': 0.00153, 'K': 0.00772, 'L': 0.04025, 'M': 0.02406, 'N': 0.06749,
+	'O': 0.07507, 'P': 0.01929, 'Q': 0.00095, 'R': 0.05987, 'S': 0.06327, 'T': 0.09056, 'U': 0.02758,
+	'V': 0.00978, 'W': 0.02360, 'X': 0.00150, 'Y': 0.01974, 'Z': 0.00074,
+}
+
+var French = map[rune]float64{
+	'A': 0.07636, 'B': 0.00901, 'C': 0.03260, 'D': 0.03669, 'E': 0.14715, 'F': 0.01066, 'G': 0.00866,
+	'H': 0.00737, 'I': 0.07529, 'J': 0.00613, 'K': 0.00074, 'L': 0.05456, 'M': 0.02968, 'N': 0.07095,
+	'O': 0.05796, 'P': 0.02521, 'Q': 0.01362, 'R': 0.06693, 'S': 0.07948, 'T': 0.07244, 'U': 0.06311,
+	'V': 0.01838, 'W': 0.00049, 'X': 0.00427, 'Y': 0.00128, 'Z': 0.00326,
+}
+
+// LanguageIndexOfCoincidence is the index of coincidence expected from the letter frequencies.
+func LanguageIndexOfCoincidence(language map[rune]float64) float64 {
+	ioc := 0.0
+	for _, p := range language {
+		ioc += p * p
+	}
+
+	return ioc
+}
@@ -0,0 +1,198 @@
+package analysis
+
+import (
+	"bufio"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var ErrNoModel = errors.New("no n-gram model was given")
+
+//go:embed opticks_bigrams.txt
+var opticksBigrams string
+
+//go:embed opticks_quadgrams.txt
+var opticksQuadgrams string
+
+var loadOpticksBigrams = lazyModel(opticksBigrams)
+var loadOpticksQuadgrams = lazyModel(opticksQuadgrams)
+
+// OpticksBigrams and OpticksQuadgrams are models of English letters counted from Newton's
+// Opticks (1730), so they lean to its older spelling and subject. No solver takes them unless
+// they are given as its model. They are parsed on first use.
+func OpticksBigrams() *NGramModel { return loadOpticksBigrams() }
+func OpticksQuadgrams() *NGramModel { return loadOpticksQuadgrams() }
+
+func lazyModel(data string) func() *NGramModel {
+	var once sync.Once
+	var model *NGramModel
+
+	return func() *NGramModel {
+		once.Do(func() {
+			counts, err := ParseNGrams(strings.NewReader(data))
+			if err == nil {
+				model, err = NewNGramModel(counts)
+			}
+			if err != nil {
+				panic("analysis: embedded n-grams: " + err.Error())
+			}
+		})
+
+		return model
+	}
+}
+
+// ParseNGrams reads an n-gram and its count per line, separated by a space. Empty lines and lines
+// starting with # are skipped.
+func ParseNGrams(r io.Reader) (map[string]int, error) {
+	counts := make(map[string]int)
+	scanner := bufio.NewScanner(r)
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: expected an n-gram and a count, got %q", line, text)
+		}
+
+		count, err := strconv.Atoi(fields[1])
+		if err != nil || count < 0 {
+			return nil, fmt.Errorf("line %d: %q is not a count", line, fields[1])
+		}
+		counts[fields[0]] += count
+	}
+
+	return counts, scanner.Err()
+}
+
+// An NGramModel scores a text by the log probabilities of its n-grams. N-grams never seen take
+// the floor, the log probability of a hundredth of a count.
+type NGramModel struct {
+	N int
+	Alphabet []rune
+	Floor float64
+	table []float64
+}
+
+func NewNGramModel(counts map[string]int) (*NGramModel, error) {
+	n, total := 0, 0
+	letters := make(map[rune]bool)
+
+	for gram, count := range counts {
+		rs := []rune(gram)
+		if n == 0 {
+			n = len(rs)
+		} else if len(rs) != n {
+			return nil, fmt.Errorf("n-grams %q and others of length %d are mixed", gram, n)
+		}
+
+		for _, r := range rs {
+			letters[r] = true
+		}
+		total += count
+	}
+
+	if total == 0 {
+		return nil, fmt.Errorf("no n-grams were counted")
+	}
+
+	m := &NGramModel{N: n, Alphabet: make([]rune, 0, len(letters)), Floor: math.Log10(0.01 / float64(total))}
+	for r := range letters {
+		m.Alphabet = append(m.Alphabet, r)
+	}
+	sort.Slice(m.Alphabet, func(i, j int) bool { return m.Alphabet[i] < m.Alphabet[j] })
+
+	size := 1
+	for i := 0; i < n; i++ {
+		size *= len(m.Alphabet)
+	}
+
+	m.table = make([]float64, size)
+	for i := range m.table {
+		m.table[i] = m.Floor
+	}
+
+	amap := buildIndexMap(m.Alphabet)
+	for gram, count := range counts {
+		if count > 0 {
+			m.table[m.index(indices([]rune(gram), amap))] = math.Log10(float64(count) / float64(total))
+		}
+	}
+
+	return m, nil
+}
+
+func indices(text []rune, amap map[rune]int) []int {
+	result := make([]int, len(text))
+	for i, r := range text {
+		result[i] = amap[r]
+	}
+
+	return result
+}
+
+func (m *NGramModel) index(gram []int) int {
+	i := 0
+	for _, g := range gram {
+		i = i * len(m.Alphabet) + g
+	}
+
+	return i
+}
+
+// Score folds the case of the text and skips the runes missing from the model.
+func (m *NGramModel) Score(text []rune) float64 {
+	return m.score(indices(foldFilter(text, m.Alphabet), buildIndexMap(m.Alphabet)))
+}
+
+// Scores runes given by their index in the model alphabet, a negative index always scores the
+// floor.
+func (m *NGramModel) score(text []int) float64 {
+	score := 0.0
+	for i := 0; i + m.N <= len(text); i++ {
+		j := 0
+		for _, g := range text[i:i + m.N] {
+			if g < 0 {
+				j = -1
+				break
+			}
+			j = j * len(m.Alphabet) + g
+		}
+
+		if j < 0 {
+			score += m.Floor
+		} else {
+			score += m.table[j]
+		}
+	}
+
+	return score
+}
+
+func (m *NGramModel) Fitness() Fitness { return m.Score }
+
+// Maps an alphabet to the model alphabet, -1 for the runes the model does not know.
+func (m *NGramModel) mapping(alphabet []rune) []int {
+	amap := buildIndexMap(m.Alphabet)
+	result := make([]int, len(alphabet))
+	for i, r := range alphabet {
+		if j, found := amap[r]; found {
+			result[i] = j
+		} else {
+			result[i] = -1
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,125 @@
+package analysis
+
+import (
+	"math"
+	"sort"
+	"unicode"
+)
+
+// Keeps the runes of the alphabet after folding their case the way the passthrough policy does.
+func foldFilter(text, alphabet []rune) []rune {
+	amap := buildIndexMap(alphabet)
+	result := make([]rune, 0, len(text))
+
+	for _, r := range text {
+		if _, found := amap[r]; found {
+			result = append(result, r)
+		} else if _, found := amap[unicode.ToUpper(r)]; found {
+			result = append(result, unicode.ToUpper(r))
+		} else if _, found := amap[unicode.ToLower(r)]; found {
+			result = append(result, unicode.ToLower(r))
+		}
+	}
+
+	return result
+}
+
+// Kasiski counts, for each period up to maxPeriod, the distances between repeated trigrams it
+// divides. The count of a period is at its index.
+func Kasiski(text, alphabet []rune, maxPeriod int) []int {
+	filtered := Filter(text, alphabet)
+	counts := make([]int, maxPeriod + 1)
+	last := make(map[string]int)
+
+	for i := 0; i + 3 <= len(filtered); i++ {
+		gram := string(filtered[i:i + 3])
+		if j, found := last[gram]; found {
+			for p := 2; p <= maxPeriod; p++ {
+				if (i - j) % p == 0 {
+					counts[p]++
+				}
+			}
+		}
+		last[gram] = i
+	}
+
+	return counts
+}
+
+// Friedman estimates the period from the index of coincidence of the whole text, it is only a
+// rough guide for short texts.
+func Friedman(text, alphabet []rune, language map[rune]float64) float64 {
+	ioc := IndexOfCoincidence(text, alphabet)
+	random := RandomIndexOfCoincidence(alphabet)
+	if ioc <= random {
+		return float64(len(Filter(text, alphabet)))
+	}
+
+	return (LanguageIndexOfCoincidence(language) - random) / (ioc - random)
+}
+
+// PeriodIndexOfCoincidence is the mean index of coincidence of the columns the text is split in
+// by the period, close to the language at the right period and to random elsewhere.
+func PeriodIndexOfCoincidence(text, alphabet []rune, period int) float64 {
+	filtered := Filter(text, alphabet)
+	amap := buildIndexMap(alphabet)
+
+	columns := make([][]int, period)
+	for i := range columns {
+		columns[i] = make([]int, len(alphabet))
+	}
+	for i, r := range filtered {
+		columns[i % period][amap[r]]++
+	}
+
+	ioc := 0.0
+	for _, counts := range columns {
+		ioc += indexOfCoincidenceCounts(counts)
+	}
+
+	return ioc / float64(period)
+}
+
+type Period struct {
+	Length int
+	IndexOfCoincidence float64
+	Kasiski int
+}
+
+// EstimatePeriods ranks the periods up to maxPeriod. The multiples of the period look as good
+// as the period itself, so the periods whose index of coincidence is nearer the language than
+// random, and within a tenth of the best one, come first from the shortest. A divisor of the
+// period mixes a few alphabets in each column and falls below. The others follow by Kasiski
+// count, then the ones nearer the Friedman estimate first, as their index of coincidence is
+// mostly noise.
+func EstimatePeriods(text, alphabet []rune, language map[rune]float64, maxPeriod int) []Period {
+	if n := len(Filter(text, alphabet)) / 2; maxPeriod > n {
+		maxPeriod = n
+	}
+
+	kasiski := Kasiski(text, alphabet, maxPeriod)
+	friedman := Friedman(text, alphabet, language)
+	threshold := (LanguageIndexOfCoincidence(language) + RandomIndexOfCoincidence(alphabet)) / 2
+
+	periods := make([]Period, 0, maxPeriod)
+	for p := 1; p <= maxPeriod; p++ {
+		periods = append(periods, Period{p, PeriodIndexOfCoincidence(text, alphabet, p), kasiski[p]})
+		threshold = math.Max(threshold, 0.9 * periods[p - 1].IndexOfCoincidence)
+	}
+
+	sort.SliceStable(periods, func(i, j int) bool {
+		a, b := periods[i], periods[j]
+		if above := a.IndexOfCoincidence >= threshold; above != (b.IndexOfCoincidence >= threshold) {
+			return above
+		} else if above {
+			return a.Length < b.Length
+		} else if a.Kasiski != b.Kasiski {
+			return a.Kasiski > b.Kasiski
+		} else if da, db := math.Abs(float64(a.Length) - friedman), math.Abs(float64(b.Length) - friedman); da != db {
+			return da < db
+		}
+		return a.IndexOfCoincidence > b.IndexOfCoincidence
+	})
+
+	return periods
+}
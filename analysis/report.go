@@ -0,0 +1,81 @@
+package analysis
+
+import (
+	"cryptochev/classical"
+	"fmt"
+	"strings"
+)
+
+// Report gathers the statistics of a text, the n-gram lists keep the most frequent ones.
+type Report struct {
+	Alphabet []rune
+	Length int
+	Counts []int
+	Bigrams []NGram
+	Trigrams []NGram
+	Quadgrams []NGram
+	IndexOfCoincidence float64
+	ChiSquared float64
+	Entropy float64
+}
+
+// NewReport keeps the top most frequent n-grams of each size, all of them when top is negative.
+func NewReport(text, alphabet []rune, language map[rune]float64, top int) *Report {
+	counts := Counts(text, alphabet)
+
+	return &Report{
+		Alphabet: alphabet,
+		Length: sum(counts),
+		Counts: counts,
+		Bigrams: Top(Bigrams(text, alphabet), top),
+		Trigrams: Top(Trigrams(text, alphabet), top),
+		Quadgrams: Top(Quadgrams(text, alphabet), top),
+		IndexOfCoincidence: IndexOfCoincidence(text, alphabet),
+		ChiSquared: ChiSquared(text, alphabet, language),
+		Entropy: Entropy(text, alphabet),
+	}
+}
+
+// AnalyzeCipher reports on the current text of the cipher, usually after encryption.
+func AnalyzeCipher(c classical.ICipherClassical, alphabet []rune, language map[rune]float64, top int) *Report {
+	return NewReport(c.GetText(), alphabet, language, top)
+}
+
+func (r *Report) String() string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "Length: %d\n", r.Length)
+	fmt.Fprintf(&sb, "Index of coincidence: %.4f (%.2f times random)\n", r.IndexOfCoincidence, r.IndexOfCoincidence / RandomIndexOfCoincidence(r.Alphabet))
+	fmt.Fprintf(&sb, "Chi-squared: %.2f\n", r.ChiSquared)
+	fmt.Fprintf(&sb, "Entropy: %.3f bits\n", r.Entropy)
+
+	max := 0
+	for _, c := range r.Counts {
+		if c > max {
+			max = c
+		}
+	}
+
+	sb.WriteString("Unigrams:\n")
+	for i, c := range r.Counts {
+		percent, bar := 0.0, 0
+		if r.Length > 0 {
+			percent = 100 * float64(c) / float64(r.Length)
+			bar = 40 * c / max
+		}
+		fmt.Fprintf(&sb, "  %c %5d %6.2f%% %s\n", r.Alphabet[i], c, percent, strings.Repeat("#", bar))
+	}
+
+	for _, list := range []struct {
+		name string
+		grams []NGram
+	}{{"Bigrams", r.Bigrams}, {"Trigrams", r.Trigrams}, {"Quadgrams", r.Quadgrams}} {
+		sb.WriteString(list.name + ":")
+		for _, g := range list.grams {
+			fmt.Fprintf(&sb, " %s %d", g.Gram, g.Count)
+		}
+		sb.WriteByte('\n')
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,72 @@
+package analysis
+
+import (
+	"cryptochev/classical"
+	"cryptochev/utils"
+)
+
+// Solvers decrypt with this policy so the candidates keep the case, the spaces and the
+// punctuation of the cipher text.
+var passthrough = classical.NewPolicy(classical.PolicyPassthrough, true, false)
+
+// BreakCaesar tries the 26 shifts and returns the n best candidates, ROT13 being the shift 13.
+func BreakCaesar(text []rune, fitness Fitness, n int) []Candidate[classical.KeyCaesar] {
+	candidates := make([]Candidate[classical.KeyCaesar], 0, 26)
+
+	for shift := 0; shift < 26; shift++ {
+		key := classical.NewKeyCaesar(shift)
+		plain, _ := classical.DecryptCaesar(key, text)
+		candidates = append(candidates, Candidate[classical.KeyCaesar]{key, plain, fitness(plain)})
+	}
+
+	return rank(candidates, n)
+}
+
+func BreakShiftAlphabet(text, alphabet []rune, fitness Fitness, n int) ([]Candidate[classical.KeyShiftAlphabet], error) {
+	candidates := make([]Candidate[classical.KeyShiftAlphabet], 0, len(alphabet))
+
+	for shift := range alphabet {
+		key := classical.NewKeyShiftAlphabet(alphabet, shift)
+		c := classical.NewShiftAlphabet(append([]rune{}, text...), key)
+		c.Cipher.Policy = passthrough
+		if err := c.DecryptE(); err != nil {
+			return nil, err
+		}
+
+		candidates = append(candidates, Candidate[classical.KeyShiftAlphabet]{key, c.GetText(), fitness(c.GetText())})
+	}
+
+	return rank(candidates, n), nil
+}
+
+// BreakAffine tries every multiplier coprime with the length of the alphabet with every shift.
+func BreakAffine(text, alphabet []rune, fitness Fitness, n int) ([]Candidate[classical.KeyAffine], error) {
+	coprimes := affineCoprimes(len(alphabet))
+	candidates := make([]Candidate[classical.KeyAffine], 0, len(coprimes) * len(alphabet))
+
+	for _, a := range coprimes {
+		for b := range alphabet {
+			key := classical.NewKeyAffine(alphabet, a, b)
+			c := classical.NewAffine(append([]rune{}, text...), key)
+			c.Cipher.Policy = passthrough
+			if err := c.DecryptE(); err != nil {
+				return nil, err
+			}
+
+			candidates = append(candidates, Candidate[classical.KeyAffine]{key, c.GetText(), fitness(c.GetText())})
+		}
+	}
+
+	return rank(candidates, n), nil
+}
+
+func affineCoprimes(n int) []int {
+	switch n {
+	case 26:
+		return classical.Alphabet26Coprimes()
+	case 36:
+		return classical.Alphabet36Coprimes()
+	}
+
+	return utils.Coprimes(n)
+}
@@ -0,0 +1,72 @@
+package analysis
+
+import "math"
+
+// IndexOfCoincidence is the probability that two runes drawn from the text are the same, about
+// 0.067 for English and 1/26 for random letters.
+func IndexOfCoincidence(text, alphabet []rune) float64 { return indexOfCoincidenceCounts(Counts(text, alphabet)) }
+
+func indexOfCoincidenceCounts(counts []int) float64 {
+	total := sum(counts)
+	if total < 2 {
+		return 0
+	}
+
+	pairs := 0
+	for _, c := range counts {
+		pairs += c * (c - 1)
+	}
+
+	return float64(pairs) / float64(total * (total - 1))
+}
+
+// RandomIndexOfCoincidence is the index of coincidence of uniformly random runes of the alphabet.
+func RandomIndexOfCoincidence(alphabet []rune) float64 { return 1 / float64(len(alphabet)) }
+
+// ChiSquared compares the counts of the text with those expected from the language. Runes of the
+// alphabet missing from the language are left out of the statistic, and the language
+// frequencies are scaled to the runes that remain, so English fits AlphabetL25 as well.
+func ChiSquared(text, alphabet []rune, language map[rune]float64) float64 {
+	return chiSquaredCounts(Counts(text, alphabet), alphabet, language)
+}
+
+func chiSquaredCounts(counts []int, alphabet []rune, language map[rune]float64) float64 {
+	total, expected := 0, 0.0
+	for i, r := range alphabet {
+		if p := language[r]; p > 0 {
+			total += counts[i]
+			expected += p
+		}
+	}
+
+	if total == 0 {
+		return 0
+	}
+
+	chi := 0.0
+	for i, r := range alphabet {
+		if p := language[r]; p > 0 {
+			e := float64(total) * p / expected
+			d := float64(counts[i]) - e
+			chi += d * d / e
+		}
+	}
+
+	return chi
+}
+
+// Entropy is the Shannon entropy of the runes in bits, at most log2 of the alphabet length.
+func Entropy(text, alphabet []rune) float64 {
+	counts := Counts(text, alphabet)
+	total := sum(counts)
+
+	entropy := 0.0
+	for _, c := range counts {
+		if c > 0 {
+			p := float64(c) / float64(total)
+			entropy -= p * math.Log2(p)
+		}
+	}
+
+	return entropy
+}
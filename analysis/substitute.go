@@ -0,0 +1,129 @@
+package analysis
+
+import (
+	"cryptochev/classical"
+	"math"
+	"sort"
+)
+
+// SubstituteOptions tunes the substitution solver. Model is required, quadgrams of the language
+// work best, the other zero values anneal from a crypto/rand seed.
+//
+// Each restart starts from a random key and tries Iterations swaps of two letters. A swap is kept
+// when it raises the score, or when the drop is small for the temperature, which falls from
+// Temperature to 0 along the restart. Repetitive texts trap a plain hill climber, which a negative
+// Temperature asks for. Progress is called at the end of each restart with the best candidate it
+// found.
+type SubstituteOptions struct {
+	Alphabet []rune
+	Model *NGramModel
+	Restarts int
+	Iterations int
+	Temperature float64
+	Random *classical.Random
+	Progress func(restart int, candidate Candidate[classical.KeySubstitute])
+}
+
+func (o *SubstituteOptions) withDefaults() *SubstituteOptions {
+	d := SubstituteOptions{Alphabet: []rune(classical.AlphabetL), Restarts: 10, Iterations: 20000, Temperature: 20}
+	if o != nil {
+		d.Model = o.Model
+		d.Random = o.Random
+		d.Progress = o.Progress
+		if o.Alphabet != nil {
+			d.Alphabet = o.Alphabet
+		}
+		if o.Restarts > 0 {
+			d.Restarts = o.Restarts
+		}
+		if o.Iterations > 0 {
+			d.Iterations = o.Iterations
+		}
+		if o.Temperature != 0 {
+			d.Temperature = o.Temperature
+		}
+	}
+
+	return &d
+}
+
+// SolveSubstitute returns the best candidate of each restart, the best first.
+func SolveSubstitute(text []rune, o *SubstituteOptions) ([]Candidate[classical.KeySubstitute], error) {
+	o = o.withDefaults()
+	if o.Model == nil {
+		return nil, ErrNoModel
+	}
+
+	n := len(o.Alphabet)
+	amap := buildIndexMap(o.Alphabet)
+	cipher := indices(foldFilter(text, o.Alphabet), amap)
+	models := o.Model.mapping(o.Alphabet)
+
+	plain := make([]int, len(cipher))
+	score := func(plainOf []int) float64 {
+		for i, c := range cipher {
+			plain[i] = models[plainOf[c]]
+		}
+		return o.Model.score(plain)
+	}
+
+	candidates := make([]Candidate[classical.KeySubstitute], 0, o.Restarts)
+
+	for restart := 0; restart < o.Restarts; restart++ {
+		plainOf := indices(o.Random.Shuffle(append([]rune{}, o.Alphabet...)), amap)
+		current := score(plainOf)
+		best, bestScore := append([]int{}, plainOf...), current
+
+		for i := 0; i < o.Iterations; i++ {
+			a, b := o.Random.Intn(n), o.Random.Intn(n)
+			if a == b {
+				continue
+			}
+
+			plainOf[a], plainOf[b] = plainOf[b], plainOf[a]
+			s := score(plainOf)
+
+			t := o.Temperature * float64(o.Iterations - i) / float64(o.Iterations)
+			if s > current || t > 0 && o.Random.Float64() < math.Exp((s - current) / t) {
+				current = s
+				if s > bestScore {
+					bestScore = s
+					copy(best, plainOf)
+				}
+			} else {
+				plainOf[a], plainOf[b] = plainOf[b], plainOf[a]
+			}
+		}
+
+		candidate, err := substituteCandidate(text, o.Alphabet, best, bestScore)
+		if err != nil {
+			return nil, err
+		}
+
+		if o.Progress != nil {
+			o.Progress(restart, candidate)
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Score > candidates[j].Score })
+
+	return candidates, nil
+}
+
+// The cipher rune at index c of the alphabet stands for the plain rune at plainOf[c].
+func substituteCandidate(text, alphabet []rune, plainOf []int, score float64) (Candidate[classical.KeySubstitute], error) {
+	sAlphabet := make([]rune, len(alphabet))
+	for c, p := range plainOf {
+		sAlphabet[p] = alphabet[c]
+	}
+
+	key := classical.NewKeySubstitute(alphabet, sAlphabet)
+	c := classical.NewSubstitute(append([]rune{}, text...), key)
+	c.Cipher.Policy = passthrough
+	if err := c.DecryptE(); err != nil {
+		return Candidate[classical.KeySubstitute]{}, err
+	}
+
+	return Candidate[classical.KeySubstitute]{key, c.GetText(), score}, nil
+}
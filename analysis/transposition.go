@@ -0,0 +1,351 @@
+package analysis
+
+import (
+	"cryptochev/classical"
+	"sort"
+	"unicode"
+)
+
+// TranspositionOptions tunes the Column and Myszkowski solvers. Model is required, bigrams of the
+// language are enough.
+//
+// Each key width from 2 to MaxWidth, at most 26, is searched for the key whose decryption reads
+// best to the model, the runes it does not know are left out. Keys up to Exhaustive wide are all
+// tried, 8 for Column and 6 for Myszkowski by default. Wider keys are climbed from Restarts random
+// keys, 10 for Column and 30 for Myszkowski, by swapping and moving columns, Myszkowski also
+// ranking one column anew. A wide Myszkowski key sharing a few ranks among many columns is seldom
+// climbed to, a wrong rank shifts the columns read after it.
+type TranspositionOptions struct {
+	Model *NGramModel
+	MaxWidth int
+	Exhaustive int
+	Restarts int
+	Random *classical.Random
+}
+
+func (o *TranspositionOptions) withDefaults(myszkowski bool) *TranspositionOptions {
+	d := TranspositionOptions{MaxWidth: 20, Exhaustive: 8, Restarts: 10}
+	if myszkowski {
+		d.Exhaustive, d.Restarts = 6, 30
+	}
+
+	if o != nil {
+		d.Model = o.Model
+		d.Random = o.Random
+		if o.MaxWidth > 0 {
+			d.MaxWidth = o.MaxWidth
+		}
+		if o.Exhaustive > 0 {
+			d.Exhaustive = o.Exhaustive
+		}
+		if o.Restarts > 0 {
+			d.Restarts = o.Restarts
+		}
+	}
+
+	return &d
+}
+
+// SolveColumn and SolveMyszkowski return the best key of each width, the best first. The last
+// row may be short, the columns are then irregular.
+func SolveColumn(text []rune, o *TranspositionOptions) ([]Candidate[classical.KeyColumn], error) {
+	o = o.withDefaults(false)
+	if o.Model == nil {
+		return nil, ErrNoModel
+	}
+
+	solutions := solveTransposition(text, false, o)
+	candidates := make([]Candidate[classical.KeyColumn], len(solutions))
+
+	for i, s := range solutions {
+		key := classical.NewKeyColumn(s.key)
+		c := classical.NewColumn(append([]rune{}, text...), key)
+		c.Decrypt()
+		candidates[i] = Candidate[classical.KeyColumn]{key, c.GetText(), s.score}
+	}
+
+	return candidates, nil
+}
+
+func SolveMyszkowski(text []rune, o *TranspositionOptions) ([]Candidate[classical.KeyMyszkowski], error) {
+	o = o.withDefaults(true)
+	if o.Model == nil {
+		return nil, ErrNoModel
+	}
+
+	solutions := solveTransposition(text, true, o)
+	candidates := make([]Candidate[classical.KeyMyszkowski], len(solutions))
+
+	for i, s := range solutions {
+		key := classical.NewKeyMyszkowski(s.key)
+		c := classical.NewMyszkowski(append([]rune{}, text...), key)
+		c.Decrypt()
+		candidates[i] = Candidate[classical.KeyMyszkowski]{key, c.GetText(), s.score}
+	}
+
+	return candidates, nil
+}
+
+type transpositionSolution struct {
+	key []rune
+	score float64
+}
+
+// A key is searched as the ranks of its columns, ranks are shared by Myszkowski columns only.
+type transposition struct {
+	model *NGramModel
+	cipher []int
+	plain []int
+	known []int
+	order []int
+	width int
+}
+
+func solveTransposition(text []rune, myszkowski bool, o *TranspositionOptions) []transpositionSolution {
+	t := &transposition{model: o.Model, cipher: modelIndices(text, o.Model), plain: make([]int, len(text)), known: make([]int, 0, len(text))}
+
+	maxWidth := o.MaxWidth
+	if maxWidth > len(text) / 2 {
+		maxWidth = len(text) / 2
+	}
+	if maxWidth > len(rankAlphabet) {
+		maxWidth = len(rankAlphabet)
+	}
+
+	solutions := make([]transpositionSolution, 0, maxWidth)
+	for t.width = 2; t.width <= maxWidth; t.width++ {
+		t.order = make([]int, t.width)
+
+		var ranks []int
+		if t.width <= o.Exhaustive {
+			ranks = t.exhaustive(myszkowski)
+		} else {
+			ranks = t.climb(myszkowski, o.Restarts, o.Random)
+		}
+
+		solutions = append(solutions, transpositionSolution{rankKey(ranks), t.score(ranks)})
+	}
+
+	sort.SliceStable(solutions, func(i, j int) bool { return solutions[i].score > solutions[j].score })
+
+	return solutions
+}
+
+// Decrypts like cryptMyszkowski, which is too slow to call for every key tried, and scores the
+// runes the model knows.
+func (t *transposition) score(ranks []int) float64 {
+	for i := range t.order {
+		j := i
+		for ; j > 0 && ranks[t.order[j - 1]] > ranks[i]; j-- {
+			t.order[j] = t.order[j - 1]
+		}
+		t.order[j] = i
+	}
+
+	c := 0
+	for i := 0; i < t.width; {
+		j := i + 1
+		for j < t.width && ranks[t.order[j]] == ranks[t.order[i]] {
+			j++
+		}
+
+		for row := 0; row < len(t.plain); row += t.width {
+			for _, col := range t.order[i:j] {
+				if row + col < len(t.plain) {
+					t.plain[row + col] = t.cipher[c]
+					c++
+				}
+			}
+		}
+		i = j
+	}
+
+	t.known = t.known[:0]
+	for _, p := range t.plain {
+		if p >= 0 {
+			t.known = append(t.known, p)
+		}
+	}
+
+	return t.model.score(t.known)
+}
+
+// Tries every order of the columns, or every ranking of them with ties for Myszkowski.
+func (t *transposition) exhaustive(myszkowski bool) []int {
+	ranks, best := make([]int, t.width), make([]int, t.width)
+	used := make([]int, t.width)
+	bestScore := 0.0
+	found := false
+
+	var next func(i int)
+	next = func(i int) {
+		if i == t.width {
+			for r := 1; r < t.width; r++ {
+				if used[r] > 0 && used[r - 1] == 0 {
+					return
+				}
+			}
+			if s := t.score(ranks); !found || s > bestScore {
+				found, bestScore = true, s
+				copy(best, ranks)
+			}
+			return
+		}
+
+		for r := 0; r < t.width; r++ {
+			if used[r] == 0 || myszkowski {
+				ranks[i] = r
+				used[r]++
+				next(i + 1)
+				used[r]--
+			}
+		}
+	}
+	next(0)
+
+	return best
+}
+
+func (t *transposition) climb(myszkowski bool, restarts int, random *classical.Random) []int {
+	var best []int
+	bestScore := 0.0
+
+	for restart := 0; restart < restarts; restart++ {
+		// Myszkowski keys mostly share ranks, they start from random ties.
+		ranks := make([]int, t.width)
+		if myszkowski {
+			for i := range ranks {
+				ranks[i] = 2 * random.Intn(t.width)
+			}
+		} else {
+			for i, r := range random.Shuffle(rankKey(identity(t.width))) {
+				ranks[i] = 2 * int(r - 'A')
+			}
+		}
+		current := t.score(ranks)
+
+		for improved := true; improved; {
+			improved = false
+			for i := 0; i < t.width; i++ {
+				for j := i + 1; j < t.width; j++ {
+					ranks[i], ranks[j] = ranks[j], ranks[i]
+					if s := t.score(ranks); s > current {
+						current, improved = s, true
+					} else {
+						ranks[i], ranks[j] = ranks[j], ranks[i]
+					}
+				}
+			}
+
+			// A run of columns in the right order is moved as a whole, one column at a time
+			// would break it.
+			for i := 0; i < t.width; i++ {
+				for j := i + 1; j <= t.width; j++ {
+					for k := 1; k < t.width - j + i + 1; k++ {
+						moved := moveColumns(ranks, i, j, k)
+						if s := t.score(moved); s > current {
+							current, improved = s, true
+							copy(ranks, moved)
+						}
+					}
+				}
+			}
+
+			if !myszkowski {
+				continue
+			}
+
+			// Ranks start apart, a column can take a rank between two others as well as share one.
+			for i := 0; i < t.width; i++ {
+				for r := 0; r < 2 * t.width; r++ {
+					kept := ranks[i]
+					ranks[i] = r
+					if s := t.score(ranks); s > current {
+						current, improved = s, true
+					} else {
+						ranks[i] = kept
+					}
+				}
+			}
+		}
+
+		if best == nil || current > bestScore {
+			best, bestScore = ranks, current
+		}
+	}
+
+	return denseRanks(best)
+}
+
+// Renumbers the ranks from 0 without gaps.
+func denseRanks(ranks []int) []int {
+	used := make([]bool, 2 * len(ranks))
+	for _, r := range ranks {
+		used[r] = true
+	}
+
+	dense, next := make([]int, len(used)), 0
+	for r, u := range used {
+		if u {
+			dense[r] = next
+			next++
+		}
+	}
+
+	result := make([]int, len(ranks))
+	for i, r := range ranks {
+		result[i] = dense[r]
+	}
+
+	return result
+}
+
+// Moves the columns from i to j, excluded, k places to the right, wrapping around the key.
+func moveColumns(ranks []int, i, j, k int) []int {
+	rest := append(append([]int{}, ranks[:i]...), ranks[j:]...)
+	at := (i + k) % (len(rest) + 1)
+
+	result := append(append([]int{}, rest[:at]...), ranks[i:j]...)
+	return append(result, rest[at:]...)
+}
+
+func identity(n int) []int {
+	result := make([]int, n)
+	for i := range result {
+		result[i] = i
+	}
+
+	return result
+}
+
+// The keys are lettered by the ranks of their columns, which caps the width at 26.
+var rankAlphabet = []rune(classical.AlphabetL)
+
+func rankKey(ranks []int) []rune {
+	key := make([]rune, len(ranks))
+	for i, r := range ranks {
+		key[i] = rankAlphabet[r]
+	}
+
+	return key
+}
+
+// Maps the runes to the model alphabet after folding their case, -1 for the others.
+func modelIndices(text []rune, m *NGramModel) []int {
+	amap := buildIndexMap(m.Alphabet)
+	result := make([]int, len(text))
+
+	for i, r := range text {
+		if j, found := amap[r]; found {
+			result[i] = j
+		} else if j, found := amap[unicode.ToUpper(r)]; found {
+			result[i] = j
+		} else if j, found := amap[unicode.ToLower(r)]; found {
+			result[i] = j
+		} else {
+			result[i] = -1
+		}
+	}
+
+	return result
+}
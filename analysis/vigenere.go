@@ -0,0 +1,278 @@
+package analysis
+
+import (
+	"cryptochev/classical"
+	"math"
+	"sort"
+)
+
+// The columns are read from the runes of the alphabet only, so the key holds over the others.
+var passthroughHold = classical.NewPolicy(classical.PolicyPassthrough, true, true)
+
+// VigenereOptions tunes the Vigenere family solvers, the zero value works on English.
+//
+// Refine climbs from the key found column by column, changing one key letter at a time while
+// the fitness of the whole text improves. Keywords turn on the keyed alphabets of the Quagmire
+// III, the alphabet keyed by each of them is solved along with the plain one.
+type VigenereOptions struct {
+	Alphabet []rune
+	Language map[rune]float64
+	Fitness Fitness
+	MaxPeriod int
+	Periods int
+	Refine bool
+	Keywords [][]rune
+}
+
+func (o *VigenereOptions) withDefaults() *VigenereOptions {
+	d := VigenereOptions{Alphabet: []rune(classical.AlphabetL), Language: English, MaxPeriod: 20, Periods: 3}
+	if o != nil {
+		d.Refine = o.Refine
+		d.Keywords = o.Keywords
+		d.Fitness = o.Fitness
+		if o.Alphabet != nil {
+			d.Alphabet = o.Alphabet
+		}
+		if o.Language != nil {
+			d.Language = o.Language
+		}
+		if o.MaxPeriod > 0 {
+			d.MaxPeriod = o.MaxPeriod
+		}
+		if o.Periods > 0 {
+			d.Periods = o.Periods
+		}
+	}
+
+	if d.Fitness == nil {
+		d.Fitness = UnigramFitness(d.Language)
+	}
+
+	return &d
+}
+
+type vigenereVariant int
+
+const (
+	variantVigenere vigenereVariant = iota
+	variantVigenereBeaufort
+	variantGronsfeld
+	variantBeaufort
+)
+
+// Index of the plain rune from the indices of the cipher rune and the key rune.
+func (v vigenereVariant) decrypt(c, k, n int) int {
+	switch v {
+	case variantVigenereBeaufort:
+		return (c + k) % n
+	case variantBeaufort:
+		return (k - c + n) % n
+	}
+
+	return (c - k + n) % n
+}
+
+// Gronsfeld keys are digits, they only shift by 0 to 9.
+func (v vigenereVariant) shifts(n int) int {
+	if v == variantGronsfeld && n > 10 {
+		return 10
+	}
+
+	return n
+}
+
+func (v vigenereVariant) key(alphabet []rune, shifts []int) []rune {
+	key := make([]rune, len(shifts))
+	for i, s := range shifts {
+		if v == variantGronsfeld {
+			key[i] = rune('0' + s)
+		} else {
+			key[i] = alphabet[s]
+		}
+	}
+
+	return key
+}
+
+type vigenereSolution struct {
+	alphabet []rune
+	key []rune
+	score float64
+}
+
+// SolveVigenere and its siblings return a candidate for each of the first estimated periods and
+// each alphabet. Candidates come in the order of the periods, then by fitness, and repeat
+// neither a key nor a key repeating a shorter one.
+func SolveVigenere(text []rune, o *VigenereOptions) []Candidate[classical.KeyVigenere] {
+	return vigenereCandidates(text, variantVigenere, o)
+}
+
+func SolveVigenereBeaufort(text []rune, o *VigenereOptions) []Candidate[classical.KeyVigenere] {
+	return vigenereCandidates(text, variantVigenereBeaufort, o)
+}
+
+func SolveVigenereGronsfeld(text []rune, o *VigenereOptions) []Candidate[classical.KeyVigenere] {
+	return vigenereCandidates(text, variantGronsfeld, o)
+}
+
+func SolveBeaufort(text []rune, o *VigenereOptions) []Candidate[classical.KeyBeaufort] {
+	solutions := solveVigenere(text, variantBeaufort, o.withDefaults())
+	candidates := make([]Candidate[classical.KeyBeaufort], len(solutions))
+
+	for i, s := range solutions {
+		key := classical.NewKeyBeaufort(s.alphabet, s.key)
+		c := classical.NewBeaufort(append([]rune{}, text...), key)
+		c.Cipher.Policy = passthroughHold
+		c.Decrypt()
+		candidates[i] = Candidate[classical.KeyBeaufort]{key, c.GetText(), s.score}
+	}
+
+	return candidates
+}
+
+func vigenereCandidates(text []rune, v vigenereVariant, o *VigenereOptions) []Candidate[classical.KeyVigenere] {
+	solutions := solveVigenere(text, v, o.withDefaults())
+	candidates := make([]Candidate[classical.KeyVigenere], len(solutions))
+
+	for i, s := range solutions {
+		key := classical.NewKeyVigenere(s.alphabet, s.key)
+		var c classical.ICipherClassical
+		switch v {
+		case variantVigenereBeaufort:
+			vc := classical.NewVigenereBeaufort(append([]rune{}, text...), key)
+			vc.Cipher.Policy = passthroughHold
+			c = vc
+		case variantGronsfeld:
+			vc := classical.NewVigenereGronsfeld(append([]rune{}, text...), key)
+			vc.Cipher.Policy = passthroughHold
+			c = vc
+		default:
+			vc := classical.NewVigenere(append([]rune{}, text...), key)
+			vc.Cipher.Policy = passthroughHold
+			c = vc
+		}
+		c.Decrypt()
+		candidates[i] = Candidate[classical.KeyVigenere]{key, c.GetText(), s.score}
+	}
+
+	return candidates
+}
+
+func solveVigenere(text []rune, v vigenereVariant, o *VigenereOptions) []vigenereSolution {
+	alphabets := [][]rune{o.Alphabet}
+	for _, keyword := range o.Keywords {
+		alphabets = append(alphabets, classical.AlphabetKey(o.Alphabet, keyword))
+	}
+
+	folded := foldFilter(text, o.Alphabet)
+	periods := EstimatePeriods(folded, o.Alphabet, o.Language, o.MaxPeriod)
+	if len(periods) > o.Periods {
+		periods = periods[:o.Periods]
+	}
+
+	solutions := make([]vigenereSolution, 0, len(periods) * len(alphabets))
+	seen := make(map[string]bool)
+
+	for _, period := range periods {
+		found := make([]vigenereSolution, 0, len(alphabets))
+
+		for _, alphabet := range alphabets {
+			indices := make([]int, len(folded))
+			amap := buildIndexMap(alphabet)
+			for i, r := range folded {
+				indices[i] = amap[r]
+			}
+
+			shifts := vigenereShifts(indices, period.Length, v, alphabet, o.Language)
+			if o.Refine {
+				refineShifts(indices, shifts, v, alphabet, o.Fitness)
+			}
+
+			shifts = shortestRepeat(shifts)
+			if id := string(alphabet) + string(v.key(alphabet, shifts)); !seen[id] {
+				seen[id] = true
+				found = append(found, vigenereSolution{alphabet, v.key(alphabet, shifts), o.Fitness(decryptShifts(indices, shifts, v, alphabet))})
+			}
+		}
+
+		sort.SliceStable(found, func(i, j int) bool { return found[i].score > found[j].score })
+		solutions = append(solutions, found...)
+	}
+
+	return solutions
+}
+
+// Each column takes the shift whose decryption has the lowest chi-squared against the language.
+func vigenereShifts(indices []int, period int, v vigenereVariant, alphabet []rune, language map[rune]float64) []int {
+	n := len(alphabet)
+	shifts := make([]int, period)
+	counts := make([]int, n)
+
+	for col := range shifts {
+		best := math.Inf(1)
+		for s := 0; s < v.shifts(n); s++ {
+			for i := range counts {
+				counts[i] = 0
+			}
+			for i := col; i < len(indices); i += period {
+				counts[v.decrypt(indices[i], s, n)]++
+			}
+
+			if chi := chiSquaredCounts(counts, alphabet, language); chi < best {
+				best = chi
+				shifts[col] = s
+			}
+		}
+	}
+
+	return shifts
+}
+
+func refineShifts(indices, shifts []int, v vigenereVariant, alphabet []rune, fitness Fitness) {
+	best := fitness(decryptShifts(indices, shifts, v, alphabet))
+
+	for improved := true; improved; {
+		improved = false
+		for col := range shifts {
+			kept := shifts[col]
+			for s := 0; s < v.shifts(len(alphabet)); s++ {
+				shifts[col] = s
+				if score := fitness(decryptShifts(indices, shifts, v, alphabet)); score > best {
+					best = score
+					kept = s
+					improved = true
+				}
+			}
+			shifts[col] = kept
+		}
+	}
+}
+
+func decryptShifts(indices, shifts []int, v vigenereVariant, alphabet []rune) []rune {
+	plain := make([]rune, len(indices))
+	for i, c := range indices {
+		plain[i] = alphabet[v.decrypt(c, shifts[i % len(shifts)], len(alphabet))]
+	}
+
+	return plain
+}
+
+// A key found at a multiple of the period repeats the key of the period.
+func shortestRepeat(shifts []int) []int {
+	for p := 1; p < len(shifts); p++ {
+		if len(shifts) % p != 0 {
+			continue
+		}
+
+		repeats := true
+		for i := p; i < len(shifts) && repeats; i++ {
+			repeats = shifts[i] == shifts[i - p]
+		}
+
+		if repeats {
+			return shifts[:p]
+		}
+	}
+
+	return shifts
+}
@@ -0,0 +1,383 @@
+package classical
+
+import (
+	"cryptochev/utils"
+	"fmt"
+)
+
+const CheckerboardDefault = "ET AON RISBCDFGHJKLMPQ/UVWXYZ."
+
+func NewKeyCheckerboard(alphabet, header []rune, escape rune) *KeyCheckerboard {
+	return &KeyCheckerboard{Alphabet: alphabet, Header: header, Escape: escape}
+}
+func NewCheckerboard(text []rune, key *KeyCheckerboard) *Checkerboard {
+	return &Checkerboard{Cipher: &CipherClassical[KeyCheckerboard]{Text: text, Key: key}}
+}
+func EncryptCheckerboard(key *KeyCheckerboard, text []rune) ([]rune, error) { return cryptPure(verifyCheckerboard, checkCheckerboard, pureCheckerboard, key, text, true) }
+func DecryptCheckerboard(key *KeyCheckerboard, text []rune) ([]rune, error) { return cryptPure(verifyCheckerboard, checkCheckerboard, pureCheckerboard, key, text, false) }
+
+func pureCheckerboard(k *KeyCheckerboard, text []rune, encrypt bool, _ *Random) ([]rune, error) {
+	if encrypt {
+		return encryptCheckerboard(text, k.Alphabet, k.Header, k.Escape)
+	}
+
+	return decryptCheckerboard(text, k.Alphabet, k.Header, k.Escape)
+}
+
+// The alphabet is laid out by rows under the header, a space in the first row leaves a blank
+// and the header digit of each blank labels one of the following rows. A digit of the text is
+// written as the escape rune followed by the digit itself.
+type KeyCheckerboard struct {
+	Alphabet []rune
+	Header []rune
+	Escape rune
+}
+
+type Checkerboard struct { Cipher *CipherClassical[KeyCheckerboard] }
+func (c *Checkerboard) GetText() []rune { return c.Cipher.Text }
+func (c *Checkerboard) GetErrors() []error { return c.Cipher.Errors }
+func (c *Checkerboard) SetText(text []rune) { c.Cipher.Text = text }
+func (c *Checkerboard) Encrypt() { c.Cipher.crypt(pureCheckerboard, true) }
+func (c *Checkerboard) Decrypt() { c.Cipher.crypt(pureCheckerboard, false) }
+func (c *Checkerboard) EncryptE() error { return c.Cipher.cryptE(verifyCheckerboard, checkCheckerboard, true, c.Encrypt) }
+func (c *Checkerboard) DecryptE() error { return c.Cipher.cryptE(verifyCheckerboard, checkCheckerboard, false, c.Decrypt) }
+func (c *Checkerboard) Verify() bool { return c.Cipher.verify(verifyCheckerboard) }
+
+func verifyCheckerboard(k *KeyCheckerboard) []error {
+	errs := collectErrors(
+		verifyAlphabet("header", k.Header),
+		verifyRunesIn("header", k.Header, []rune("0123456789")),
+	)
+
+	if len(k.Header) == 0 {
+		return errs
+	}
+
+	width := len(k.Header)
+	blanks := 0
+	for i := 0; i < width && i < len(k.Alphabet); i++ {
+		if k.Alphabet[i] == ' ' {
+			blanks++
+		}
+	}
+
+	if blanks == 0 || blanks == width {
+		errs = append(errs, keyError("first row of the alphabet has %d blanks, it needs between 1 and %d", blanks, width - 1))
+	}
+
+	if len(k.Alphabet) != width * (blanks + 1) {
+		errs = append(errs, keyError("alphabet of %d runes does not fill %d rows of %d", len(k.Alphabet), blanks + 1, width))
+	}
+
+	runes := make([]rune, 0, len(k.Alphabet))
+	for _, r := range k.Alphabet {
+		if r != ' ' {
+			runes = append(runes, r)
+		}
+	}
+
+	if err := verifyAlphabet("alphabet", runes); err != nil {
+		errs = append(errs, err)
+	}
+
+	if k.Escape != 0 && utils.IndexOf(runes, k.Escape) == -1 {
+		errs = append(errs, keyError("escape %q is not in the alphabet", k.Escape))
+	}
+
+	return errs
+}
+
+func checkCheckerboard(k *KeyCheckerboard, text []rune, encrypt bool) error {
+	var err error
+	if encrypt {
+		_, err = encryptCheckerboard(text, k.Alphabet, k.Header, k.Escape)
+	} else {
+		_, err = decryptCheckerboard(text, k.Alphabet, k.Header, k.Escape)
+	}
+
+	return err
+}
+
+func buildCheckerboard(alphabet, header []rune) (map[rune][]rune, map[rune]bool) {
+	codes := make(map[rune][]rune, len(alphabet))
+	rows := make(map[rune]bool)
+	labels := make([]rune, 0, 2)
+
+	for i, r := range alphabet {
+		col := header[i % len(header)]
+
+		if i < len(header) {
+			if r == ' ' {
+				rows[col] = true
+				labels = append(labels, col)
+			} else {
+				codes[r] = []rune{col}
+			}
+		} else if row := i / len(header) - 1; row < len(labels) {
+			codes[r] = []rune{labels[row], col}
+		}
+	}
+
+	return codes, rows
+}
+
+func encryptCheckerboard(text, alphabet, header []rune, escape rune) ([]rune, error) {
+	codes, _ := buildCheckerboard(alphabet, header)
+	result := make([]rune, 0, len(text) * 2)
+
+	for i, r := range text {
+		if code, found := codes[r]; found {
+			result = append(result, code...)
+		} else if escape != 0 && r >= '0' && r <= '9' {
+			result = append(append(result, codes[escape]...), r)
+		} else {
+			return nil, &InvalidRuneError{Position: i, Rune: r}
+		}
+	}
+
+	return result, nil
+}
+
+func decryptCheckerboard(text, alphabet, header []rune, escape rune) ([]rune, error) {
+	codes, rows := buildCheckerboard(alphabet, header)
+	runes := make(map[string]rune, len(codes))
+	for r, code := range codes {
+		runes[string(code)] = r
+	}
+
+	result := make([]rune, 0, len(text))
+	for i := 0; i < len(text); i++ {
+		start := i
+		if rows[text[i]] {
+			i++
+		}
+
+		if i >= len(text) {
+			return nil, fmt.Errorf("%w: code at position %d is incomplete", ErrInvalidText, start)
+		}
+
+		r, found := runes[string(text[start:i + 1])]
+		if !found {
+			return nil, &InvalidRuneError{Position: start, Rune: text[start]}
+		}
+
+		if escape != 0 && r == escape {
+			i++
+			if i >= len(text) || text[i] < '0' || text[i] > '9' {
+				return nil, fmt.Errorf("%w: escape at position %d is not followed by a digit", ErrInvalidText, start)
+			}
+			r = text[i]
+		}
+
+		result = append(result, r)
+	}
+
+	return result, nil
+}
+
+func NewKeyVIC(phrase, date []rune, personal int, indicator, alphabet []rune, escape rune) *KeyVIC {
+	return &KeyVIC{Phrase: phrase, Date: date, Personal: personal, Indicator: indicator, Alphabet: alphabet, Escape: escape}
+}
+func NewVIC(text []rune, key *KeyVIC) *VIC { return &VIC{Cipher: &CipherClassical[KeyVIC]{Text: text, Key: key}} }
+func EncryptVIC(key *KeyVIC, text []rune) ([]rune, error) { return cryptPure(verifyVIC, checkVIC, pureVIC, key, text, true) }
+func DecryptVIC(key *KeyVIC, text []rune) ([]rune, error) { return cryptPure(verifyVIC, checkVIC, pureVIC, key, text, false) }
+
+func pureVIC(k *KeyVIC, text []rune, encrypt bool, random *Random) ([]rune, error) {
+	if encrypt {
+		return encryptVIC(text, k, random)
+	}
+
+	return decryptVIC(text, k)
+}
+
+// The first 20 runes of the phrase, the 6 digits of the date, the personal number and the 5 digits
+// of the indicator give the header of the checkerboard and the two transposition keys. The indicator
+// is only needed to encrypt, decryption reads it back from the ciphertext.
+type KeyVIC struct {
+	Phrase []rune
+	Date []rune
+	Personal int
+	Indicator []rune
+	Alphabet []rune
+	Escape rune
+}
+
+type VIC struct { Cipher *CipherClassical[KeyVIC] }
+func (c *VIC) GetText() []rune { return c.Cipher.Text }
+func (c *VIC) GetErrors() []error { return c.Cipher.Errors }
+func (c *VIC) SetText(text []rune) { c.Cipher.Text = text }
+func (c *VIC) Encrypt() { c.Cipher.crypt(pureVIC, true) }
+func (c *VIC) Decrypt() { c.Cipher.crypt(pureVIC, false) }
+func (c *VIC) EncryptE() error { return c.Cipher.cryptE(verifyVIC, checkVIC, true, c.Encrypt) }
+func (c *VIC) DecryptE() error { return c.Cipher.cryptE(verifyVIC, checkVIC, false, c.Decrypt) }
+func (c *VIC) Verify() bool { return c.Cipher.verify(verifyVIC) }
+
+func verifyVIC(k *KeyVIC) []error {
+	errs := collectErrors(
+		verifyMin("phrase length", len(k.Phrase), 20),
+		verifyLength("date", k.Date, 6),
+		verifyRunesIn("date", k.Date, []rune("0123456789")),
+		verifyMin("personal number", k.Personal, 1),
+		verifyLength("indicator", k.Indicator, 5),
+		verifyRunesIn("indicator", k.Indicator, []rune("0123456789")),
+	)
+
+	if k.Personal > 16 {
+		errs = append(errs, keyError("personal number must be at most 16, got %d", k.Personal))
+	}
+
+	return append(errs, verifyCheckerboard(&KeyCheckerboard{Alphabet: k.Alphabet, Header: []rune("0123456789"), Escape: k.Escape})...)
+}
+
+func checkVIC(k *KeyVIC, text []rune, encrypt bool) error {
+	var err error
+	if encrypt {
+		_, err = encryptVIC(text, k, nil)
+	} else {
+		_, err = decryptVIC(text, k)
+	}
+
+	return err
+}
+
+// Each new digit is the sum modulo 10 of the two digits starting at the same position.
+func ChainAdd(digits []int, length int) []int {
+	result := make([]int, length)
+	copy(result, digits)
+
+	for i := len(digits); i < length; i++ {
+		result[i] = (result[i - len(digits)] + result[i - len(digits) + 1]) % 10
+	}
+
+	return result
+}
+
+// Numbers the runes from 1 in alphabetical order, ties from left to right, and writes 10 as 0.
+func Sequentialize(rs []rune) []int {
+	positions := getSortedKeyPositions(rs)
+	result := make([]int, len(rs))
+
+	for i, p := range positions {
+		result[i] = (p + 1) % 10
+	}
+
+	return result
+}
+
+func toDigits(rs []rune) []int {
+	result := make([]int, len(rs))
+	for i, r := range rs {
+		result[i] = int(r - '0')
+	}
+
+	return result
+}
+
+// The digit 0 sorts after 9, as it stands for 10.
+func toDigitKey(digits []int) []rune {
+	result := make([]rune, len(digits))
+	for i, d := range digits {
+		result[i] = '0' + rune(d)
+		if d == 0 {
+			result[i] = '9' + 1
+		}
+	}
+
+	return result
+}
+
+func vicKeys(k *KeyVIC, indicator []rune) (header, key1, key2 []rune) {
+	a, b := toDigits(indicator), toDigits(k.Date[:5])
+	c := make([]int, 5)
+	for i := range c {
+		c[i] = utils.Mod(a[i] - b[i], 10)
+	}
+
+	e1, e2 := Sequentialize(k.Phrase[:10]), Sequentialize(k.Phrase[10:20])
+	f := ChainAdd(c, 10)
+
+	h := make([]int, 10)
+	for i := range h {
+		// The second row of F is 1234567890, so digit g sits at position g - 1.
+		h[i] = e2[utils.Mod((e1[i] + f[i]) % 10 - 1, 10)]
+	}
+
+	j := Sequentialize(toDigitKey(h))
+	kp := ChainAdd(h, 60)[10:]
+	p := kp[40:]
+
+	last := 9
+	prev := last - 1
+	for prev > 0 && p[prev] == p[last] {
+		prev--
+	}
+	width1, width2 := k.Personal + p[prev], k.Personal + p[last]
+
+	columns := make([]int, 0, 50)
+	for _, col := range getSortedKeyIndices(toDigitKey(j)) {
+		for row := 0; row < 5; row++ {
+			columns = append(columns, kp[row * 10 + col])
+		}
+	}
+
+	header = make([]rune, 10)
+	for i, d := range Sequentialize(toDigitKey(p)) {
+		header[i] = '0' + rune(d)
+	}
+
+	return header, toDigitKey(columns[:width1]), toDigitKey(columns[width1:width1 + width2])
+}
+
+func vicIndicatorPosition(k *KeyVIC, length int) int {
+	position := length - 5 * int(k.Date[5] - '0')
+	if position < 0 {
+		return 0
+	}
+
+	return position
+}
+
+// The digits are completed to groups of 5 with nulls before the transpositions, so that the
+// indicator is counted in whole groups. The nulls are single digit codes, decryption gives them
+// back as a few runes after the text.
+func encryptVIC(text []rune, k *KeyVIC, random *Random) ([]rune, error) {
+	header, key1, key2 := vicKeys(k, k.Indicator)
+
+	result, err := encryptCheckerboard(text, k.Alphabet, header, k.Escape)
+	if err != nil {
+		return nil, err
+	}
+
+	nulls := make([]rune, 0, len(header))
+	for i, r := range k.Alphabet[:len(header)] {
+		if r != ' ' && r != k.Escape {
+			nulls = append(nulls, header[i])
+		}
+	}
+
+	for len(result) % 5 != 0 {
+		result = append(result, random.RuneFrom(nulls))
+	}
+
+	result = encryptColumnDLine(cryptColumn(result, key1, true), key2, true)
+	position := vicIndicatorPosition(k, len(result))
+
+	return append(append(append(make([]rune, 0, len(result) + 5), result[:position]...), k.Indicator...), result[position:]...), nil
+}
+
+func decryptVIC(text []rune, k *KeyVIC) ([]rune, error) {
+	if len(text) < 5 {
+		return nil, &InvalidLengthError{Length: len(text), Multiple: 5}
+	}
+
+	if err := checkAlphabet(text, []rune("0123456789")); err != nil {
+		return nil, err
+	}
+
+	position := vicIndicatorPosition(k, len(text) - 5)
+	indicator := text[position:position + 5]
+	body := append(append(make([]rune, 0, len(text) - 5), text[:position]...), text[position + 5:]...)
+
+	header, key1, key2 := vicKeys(k, indicator)
+	return decryptCheckerboard(cryptColumn(decryptColumnDLine(body, key2, true), key1, false), k.Alphabet, header, k.Escape)
+}
@@ -4,18 +4,96 @@ type CipherClassical[K CipherClassicalKey] struct {
 	Text   []rune
 	Errors []error
 	Key    *K
+	Policy *Policy
+	Random *Random
 }
 
 type ICipherClassical interface {
 	GetText() []rune
 	GetErrors() []error
+	SetText(text []rune)
 	Encrypt()
 	Decrypt()
+	EncryptE() error
+	DecryptE() error
 	Verify() bool
 }
 
 type KeyNone struct{}
 
+func (c *CipherClassical[K]) cryptE(verify func(*K) []error, check func(*K, []rune, bool) error, encrypt bool, crypt func()) error {
+	if c.Key == nil {
+		return keyError("missing key")
+	}
+
+	if errs := verify(c.Key); len(errs) > 0 {
+		return errs[0]
+	}
+
+	if check != nil && c.Policy == nil {
+		if err := check(c.Key, c.Text, encrypt); err != nil {
+			return err
+		}
+	}
+
+	errs := len(c.Errors)
+	crypt()
+
+	if len(c.Errors) > errs {
+		err := c.Errors[errs]
+		c.Errors = c.Errors[:errs]
+		return err
+	}
+
+	return nil
+}
+
+// A pure crypt function of a cipher returns the text crypted with the key, it never writes to its
+// input or key. Random supplies the nulls of the ciphers which pad, nil draws from crypto/rand.
+type pureCrypt[K CipherClassicalKey] func(key *K, text []rune, encrypt bool, random *Random) ([]rune, error)
+
+// The pure functions verify the key and check the text before crypting a copy of it, the structs
+// call the same crypt functions on their text.
+func cryptPure[K CipherClassicalKey](verify func(*K) []error, check func(*K, []rune, bool) error, crypt pureCrypt[K], key *K, text []rune, encrypt bool) ([]rune, error) {
+	if key == nil {
+		return nil, keyError("missing key")
+	}
+
+	if verify != nil {
+		if errs := verify(key); len(errs) > 0 {
+			return nil, errs[0]
+		}
+	}
+
+	if check != nil {
+		if err := check(key, text, encrypt); err != nil {
+			return nil, err
+		}
+	}
+
+	return crypt(key, append([]rune{}, text...), encrypt, nil)
+}
+
+func cryptPureNone(crypt pureCrypt[KeyNone], text []rune, encrypt bool) ([]rune, error) {
+	return cryptPure(nil, nil, crypt, &KeyNone{}, text, encrypt)
+}
+
+func (c *CipherClassical[K]) crypt(crypt pureCrypt[K], encrypt bool) { c.setText(crypt(c.Key, c.Text, encrypt, c.Random)) }
+
+// Binds a pure crypt function to the key and random of the cipher, for the policy to run.
+func (c *CipherClassical[K]) pure(crypt pureCrypt[K], encrypt bool) func([]rune) ([]rune, error) {
+	return func(text []rune) ([]rune, error) { return crypt(c.Key, text, encrypt, c.Random) }
+}
+
+func (c *CipherClassical[K]) setText(text []rune, err error) {
+	if err != nil {
+		c.Errors = append(c.Errors, err)
+		return
+	}
+
+	c.Text = text
+}
+
 type CipherClassicalKey interface {
 	KeyNone |
 		KeyADFGVX |
@@ -42,5 +120,16 @@ type CipherClassicalKey interface {
 		KeyHill |
 		KeyTwoSquareV |
 		KeyTwoSquareH |
-		KeyFourSquare
+		KeyFourSquare |
+		KeyBifid |
+		KeyTrifid |
+		KeyNihilist |
+		KeyNihilistTransposition |
+		KeyCheckerboard |
+		KeyVIC |
+		KeyEnigma |
+		KeyRotorMachine |
+		KeySIGABA |
+		KeyM209 |
+		KeySolitaire
 }
\ No newline at end of file
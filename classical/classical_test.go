@@ -1,7 +1,12 @@
 package classical
 
 import (
+	"bytes"
 	"cryptochev/utils"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
 	"regexp"
 	"strings"
 	"testing"
@@ -17,13 +22,21 @@ func errorTest(t *testing.T, msg string, exp string, res string) {
 }
 
 func testCipher(t *testing.T, c ICipherClassical, exp string, dexp string) {
-	c.Encrypt()
+	if !c.Verify() {
+		t.Errorf("Verify failed: %v", c.GetErrors())
+	}
+
+	if err := c.EncryptE(); err != nil {
+		t.Errorf("Encrypt failed: %s", err)
+	}
 	ciphertext := string(c.GetText())
 	if ciphertext != exp {
 		errorTest(t, "Encrypt failed", exp, ciphertext)
 	}
 
-	c.Decrypt()
+	if err := c.DecryptE(); err != nil {
+		t.Errorf("Decrypt failed: %s", err)
+	}
 	plaintext := string(c.GetText())
 	if plaintext != dexp {
 		errorTest(t, "Decrypt failed", dexp, plaintext)
@@ -31,7 +44,13 @@ func testCipher(t *testing.T, c ICipherClassical, exp string, dexp string) {
 }
 
 func testCipherRegex(t *testing.T, c ICipherClassical, regex string, dregex string) {
-	c.Encrypt()
+	if !c.Verify() {
+		t.Errorf("Verify failed: %v", c.GetErrors())
+	}
+
+	if err := c.EncryptE(); err != nil {
+		t.Errorf("Encrypt failed: %s", err)
+	}
 	ciphertext := string(c.GetText())
 	matched, err := regexp.MatchString(regex, ciphertext)
 	if err != nil {
@@ -40,7 +59,9 @@ func testCipherRegex(t *testing.T, c ICipherClassical, regex string, dregex stri
 		errorTest(t, "Encrypt failed", regex, ciphertext)
 	}
 
-	c.Decrypt()
+	if err := c.DecryptE(); err != nil {
+		t.Errorf("Decrypt failed: %s", err)
+	}
 	plaintext := string(c.GetText())
 	matched, err = regexp.MatchString(dregex, plaintext)
 	if err != nil {
@@ -76,6 +97,17 @@ func TestClassical(t *testing.T) {
 	t.Run("TestPolybius", testPolybius)
 	t.Run("TestADFGX", testADFGX)
 	t.Run("TestADFGVX", testADFGVX)
+	t.Run("TestBifid", testBifid)
+	t.Run("TestTrifid", testTrifid)
+	t.Run("TestNihilist", testNihilist)
+	t.Run("TestNihilistTransposition", testNihilistTransposition)
+	t.Run("TestCheckerboard", testCheckerboard)
+	t.Run("TestVIC", testVIC)
+	t.Run("TestEnigma", testEnigma)
+	t.Run("TestRotorMachine", testRotorMachine)
+	t.Run("TestSIGABA", testSIGABA)
+	t.Run("TestM209", testM209)
+	t.Run("TestSolitaire", testSolitaire)
 	t.Run("TestAutokey", testAutokey)
 	t.Run("TestPlayfair", testPlayfair)
 	t.Run("TestAffine", testAffine)
@@ -87,6 +119,16 @@ func TestClassical(t *testing.T) {
 	t.Run("TestTwoSquareV", testTwoSquareV)
 	t.Run("TestTwoSquareH", testTwoSquareH)
 	t.Run("TestFourSquare", testFourSquare)
+	t.Run("TestVerify", testVerify)
+	t.Run("TestCryptErrors", testCryptErrors)
+	t.Run("TestPolicy", testPolicy)
+	t.Run("TestRegistry", testRegistry)
+	t.Run("TestKeyEncoding", testKeyEncoding)
+	t.Run("TestPipeline", testPipeline)
+	t.Run("TestStream", testStream)
+	t.Run("TestRandom", testRandom)
+	t.Run("TestRandomKeys", testRandomKeys)
+	t.Run("TestPure", testPure)
 }
 
 func testSubstitute(t *testing.T) {
@@ -428,6 +470,419 @@ func testADFGVX(t *testing.T) {
 	}
 }
 
+func testBifid(t *testing.T) {
+	c := NewBifid([]rune("FLEEATONCE"), NewKeyBifid([]rune("BGWKZQPNDSIOAXEFCLUMTHYVR"), 0))
+	testCipher(t, c, "UAEOLWRINS", "FLEEATONCE")
+
+	c = NewBifid([]rune("DEFENDTHEEASTWALLOFTHECASTLE"), NewKeyBifid([]rune("PHQGMEAYLNOFDXKRCVSZWBUTI"), 5))
+	testCipher(t, c, "FFYHMKHYCPLIASHADTRLHCCHLBLR", "DEFENDTHEEASTWALLOFTHECASTLE")
+
+	for _, test := range tests {
+		c := NewBifid([]rune(test), NewKeyBifid(AlphabetKeyL36([]rune("BIFID")), 7))
+		c.Encrypt()
+		c.Decrypt()
+		if string(c.GetText()) != test {
+			errorTest(t, "Bifid 6x6 round trip failed", test, string(c.GetText()))
+		}
+	}
+}
+
+func testTrifid(t *testing.T) {
+	alphabet := AlphabetKeyL27([]rune("FELIXMARIEDELASTELLE"), '+')
+	if string(alphabet) != "FELIXMARDSTBCGHJKNOPQUVWYZ+" {
+		t.Errorf("Trifid alphabet key failed: %s", string(alphabet))
+	}
+
+	c := NewTrifid([]rune("AIDETOILECIELTAIDERA"), NewKeyTrifid(alphabet, 5))
+	testCipher(t, c, "FMJFVOISSUFTFPUFEQQC", "AIDETOILECIELTAIDERA")
+
+	for _, test := range tests {
+		text := strings.NewReplacer("1", "+", "2", "+", "0", "+").Replace(test)
+		c := NewTrifid([]rune(text), NewKeyTrifid(AlphabetKeyL27([]rune("TRIFID"), '+'), 0))
+		c.Encrypt()
+		c.Decrypt()
+		if string(c.GetText()) != text {
+			errorTest(t, "Trifid round trip failed", text, string(c.GetText()))
+		}
+	}
+}
+
+func testNihilist(t *testing.T) {
+	key := NewKeyNihilist(AlphabetKeyL25([]rune("ZEBRAS")), []rune("12345"), []rune("RUSSIAN"))
+	c := NewNihilist([]rune("DYNAMITEWINTERPALACE"), key)
+	testCipher(t, c, "37 106 62 36 67 47 86 26 104 53 62 77 27 55 57 66 55 36 54 27", "DYNAMITEWINTERPALACE")
+
+	for _, test := range tests {
+		c := NewNihilist([]rune(test), NewKeyNihilist(AlphabetKeyL36([]rune("NIHILIST")), []rune("123456"), []rune("KEY")))
+		c.Encrypt()
+		c.Decrypt()
+		if string(c.GetText()) != test {
+			errorTest(t, "Nihilist round trip failed", test, string(c.GetText()))
+		}
+	}
+
+	if numbers, err := ParseNumbers([]rune(" 12  7\t110 ")); err != nil || !reflect.DeepEqual(numbers, []int{12, 7, 110}) || string(FormatNumbers(numbers)) != "12 7 110" {
+		t.Errorf("Numbers were not parsed and rendered: %v, %v", numbers, err)
+	}
+
+	c = NewNihilist([]rune("37 106 6X"), key)
+	var rerr *InvalidRuneError
+	if err := c.DecryptE(); !errors.As(err, &rerr) || rerr.Position != 8 {
+		t.Errorf("Nihilist did not reject the rune at position 8: %v", err)
+	}
+
+	c = NewNihilist([]rune("37 9"), key)
+	var nerr *InvalidNumberError
+	if err := c.DecryptE(); !errors.As(err, &nerr) || nerr.Position != 1 || nerr.Number != 9 {
+		t.Errorf("Nihilist did not reject the number 9: %v", err)
+	}
+}
+
+func testNihilistTransposition(t *testing.T) {
+	c := NewNihilistTransposition([]rune("ABCDEFGHI"), NewKeyNihilistTransposition([]rune("CAB"), false))
+	testCipher(t, c, "EFDHIGBCA", "ABCDEFGHI")
+
+	c = NewNihilistTransposition([]rune("ABCDEFGHI"), NewKeyNihilistTransposition([]rune("CAB"), true))
+	testCipher(t, c, "EHBFICDGA", "ABCDEFGHI")
+
+	for _, test := range tests {
+		for _, byColumns := range [...]bool{false, true} {
+			c := NewNihilistTransposition([]rune(test), NewKeyNihilistTransposition([]rune("RUSSIA"), byColumns))
+			c.Encrypt()
+			c.Decrypt()
+			if string(c.GetText()) != test {
+				errorTest(t, "Nihilist transposition round trip failed", test, string(c.GetText()))
+			}
+		}
+	}
+}
+
+func testCheckerboard(t *testing.T) {
+	key := NewKeyCheckerboard([]rune(CheckerboardDefault), []rune("0123456789"), '/')
+	testCipher(t, NewCheckerboard([]rune("ATTACKATDAWN"), key), "3113212731223655", "ATTACKATDAWN")
+	testCipher(t, NewCheckerboard([]rune("AT1200"), key), "31621622620620", "AT1200")
+
+	for _, test := range tests {
+		c := NewCheckerboard([]rune(test), NewKeyCheckerboard([]rune("FKM CPDYEHBIGQROSAZLUTJNWVX/."), []rune("9876543210"), '/'))
+		c.Encrypt()
+		c.Decrypt()
+		if string(c.GetText()) != test {
+			errorTest(t, "Checkerboard round trip failed", test, string(c.GetText()))
+		}
+	}
+
+	if err := NewCheckerboard([]rune("A1"), NewKeyCheckerboard([]rune(CheckerboardDefault), []rune("0123456789"), 0)).EncryptE(); !errors.Is(err, ErrInvalidText) {
+		t.Errorf("Checkerboard without escape did not reject a digit: %v", err)
+	}
+
+	for _, text := range [...]string{"312", "362", "3X"} {
+		if err := NewCheckerboard([]rune(text), key).DecryptE(); !errors.Is(err, ErrInvalidText) {
+			t.Errorf("Checkerboard did not reject %s: %v", text, err)
+		}
+	}
+}
+
+func testVIC(t *testing.T) {
+	if digits := ChainAdd([]int{4, 8, 5, 8, 7}, 10); !reflect.DeepEqual(digits, []int{4, 8, 5, 8, 7, 2, 3, 3, 5, 9}) {
+		t.Errorf("Chain addition failed: %v", digits)
+	}
+
+	if digits := Sequentialize([]rune("TWASTHENIG")); !reflect.DeepEqual(digits, []int{8, 0, 1, 7, 9, 4, 2, 6, 5, 3}) {
+		t.Errorf("Sequentialization failed: %v", digits)
+	}
+
+	key := NewKeyVIC([]rune("TWASTHENIGHTBEFORECHRISTMAS"), []rune("391742"), 6, []rune("77651"), []rune(CheckerboardDefault), '/')
+
+	// The key derivation worked by hand through the lines of the Hayhanen procedure: H is 0485885270,
+	// J is 9263784150 and the last row P is 8902377051, which gives widths of 6 + 5 and 6 + 1.
+	header, key1, key2 := vicKeys(key, key.Indicator)
+	digits := func(rs []rune) string { return strings.ReplaceAll(string(rs), ":", "0") }
+	if string(header) != "7892356041" || digits(key1) != "96780251693" || digits(key2) != "9872762" {
+		t.Errorf("VIC key derivation failed: %s, %s, %s", string(header), digits(key1), digits(key2))
+	}
+
+	for _, test := range tests {
+		c := NewVIC([]rune(test), key)
+		if !c.Verify() {
+			t.Fatalf("VIC key did not verify: %v", c.GetErrors())
+		}
+
+		if err := c.EncryptE(); err != nil {
+			t.Fatalf("VIC encryption failed: %v", err)
+		}
+
+		ciphertext := string(c.GetText())
+		if len(ciphertext) % 5 != 0 {
+			t.Errorf("VIC nulls did not complete the last group of %s", ciphertext)
+		}
+		if position := len(ciphertext) - 15; ciphertext[position:position + 5] != "77651" {
+			t.Errorf("VIC indicator is not 2 groups before the end of %s", ciphertext)
+		}
+
+		if err := c.DecryptE(); err != nil || !vicNulled(string(c.GetText()), test) {
+			errorTest(t, "VIC round trip failed", test, string(c.GetText()))
+		}
+	}
+
+	// Without nulls the digits of this text need a whole number of groups.
+	text := []rune("WEAREDISCOVERED")
+	c1, _ := EncryptVIC(key, text)
+	if e, _ := encryptCheckerboard(text, []rune(CheckerboardDefault), header, '/'); len(e) % 5 != 0 {
+		t.Fatalf("VIC text needs nulls: %s", string(e))
+	}
+
+	other := *key
+	other.Indicator = []rune("12345")
+	c2, _ := EncryptVIC(&other, text)
+	if plain, err := DecryptVIC(key, c2); err != nil || string(plain) != string(text) || string(c1) == string(c2) {
+		t.Errorf("VIC indicator did not change the transpositions: %s, %s, %v", string(c1), string(c2), err)
+	}
+}
+
+// The nulls completing the last group of 5 digits come back as up to 4 runes after the text.
+func vicNulled(text, plain string) bool {
+	return strings.HasPrefix(text, plain) && len(text) - len(plain) < 5
+}
+
+func testEnigma(t *testing.T) {
+	c := NewEnigma([]rune("AAAAA"), NewKeyEnigma("B", []string{"I", "II", "III"}, []rune("AAA"), []rune("AAA"), nil))
+	testCipher(t, c, "BDZGO", "AAAAA")
+
+	// Operation Barbarossa, 1941
+	c = NewEnigma([]rune("AUFKLXABTEILUNGXVONXKURTINOWAXKURTINOWAXNORDWESTLXSEBEZXSEBEZXUAFFLIEGERSTRASZERIQTUNGXDUBROWKIXDUBROWKIXOPOTSCHKAXOPOTSCHKAXUMXEINSAQTDREINULLXUHRANGETRETENXANGRIFFXINFXRGTX"),
+		NewKeyEnigma("B", []string{"II", "IV", "V"}, []rune("BUL"), []rune("BLA"), []rune("AV BS CG DL FU HZ IN KM OW RX")))
+	testCipher(t, c, "EDPUDNRGYSZRCXNUYTPOMRMBOFKTBZREZKMLXLVEFGUEYSIOZVEQMIKUBPMMYLKLTTDEISMDICAGYKUACTCDOMOHWXMUUIAUBSTSLRNBZSZWNRFXWFYSSXJZVIJHIDISHPRKLKAYUPADTXQSPINQMATLPIFSVKDASCTACDPBOPVHJK",
+		string(c.GetText()))
+
+	// U-534, M4
+	c = NewEnigma([]rune("VONVONJLOOKSJHFFTTTEINSEINSDREIZWOYYQNNSNEUNINHALTXXBEIANGRIFFUNTERWASSERGEDRUECKTYWABOSXLETZTER"),
+		NewKeyEnigma("B-thin", []string{"Beta", "II", "IV", "I"}, []rune("AAAV"), []rune("VJNA"), []rune("AT BL DF GJ HM NW OP QY RZ VX")))
+	testCipher(t, c, "NCZWVUSXPNYMINHZXMQXSFWXWLKJAHSHNMCOCCAKUQPMKCSMHKSEINJUSBLKIOSXCKUBHMLLXCSJUSRRDVKOHULXWCCBGVLI", string(c.GetText()))
+
+	m := newRotorMachine(enigmaRotorMachine(NewKeyEnigma("B", []string{"I", "II", "III"}, []rune("AAA"), []rune("ADU"), nil)))
+	for _, exp := range [...]string{"ADV", "AEW", "BFX", "BFY"} {
+		m.step(m)
+		if positions := string([]rune{'A' + rune(m.rotors[0].position), 'A' + rune(m.rotors[1].position), 'A' + rune(m.rotors[2].position)}); positions != exp {
+			errorTest(t, "Enigma did not double step", exp, positions)
+		}
+	}
+
+	for _, test := range tests[2:] {
+		m3, _ := EncryptEnigma(NewKeyEnigma("B", []string{"VI", "VII", "VIII"}, []rune("XYZ"), []rune("QEV"), []rune("PO ML IU KJ NH YT")), []rune(test))
+		m4, _ := EncryptEnigma(NewKeyEnigma("B-thin", []string{"Beta", "VI", "VII", "VIII"}, []rune("AXYZ"), []rune("AQEV"), []rune("PO ML IU KJ NH YT")), []rune(test))
+		if string(m3) != string(m4) {
+			errorTest(t, "M4 with Beta at A and thin B differs from M3", string(m3), string(m4))
+		}
+	}
+
+	for _, name := range EnigmaRotors() {
+		if rotor := []rune(enigmaRotors[name].wiring); len(rotor) != 26 || verifyAlphabet(name, rotor) != nil || verifyRunesIn(name, rotor, []rune(AlphabetL)) != nil {
+			t.Errorf("Rotor %s is not a permutation", name)
+		}
+	}
+
+	for _, name := range EnigmaReflectors() {
+		reflector := enigmaReflectors[name]
+		for i, r := range reflector {
+			if rune(reflector[r - 'A']) != 'A' + rune(i) || r == 'A' + rune(i) {
+				t.Errorf("Reflector %s does not swap pairs at %c", name, 'A' + rune(i))
+			}
+		}
+	}
+}
+
+func testRotorMachine(t *testing.T) {
+	rotors := []KeyRotor{
+		{Wiring: []rune("BCDA"), Notches: []rune("D"), Position: 'C'},
+		{Wiring: []rune("CADB"), Notches: []rune("B"), Position: 'A'},
+	}
+	m := newRotorMachine(NewKeyRotorMachine([]rune("ABCD"), nil, rotors, nil, SteppingOdometer))
+	for _, exp := range [...]string{"CB", "DC", "DD", "DA", "DB"} {
+		m.step(m)
+		if positions := string([]rune{'A' + rune(m.rotors[0].position), 'A' + rune(m.rotors[1].position)}); positions != exp {
+			errorTest(t, "Odometer did not carry", exp, positions)
+		}
+	}
+
+	reversed := newRotor(&KeyRotor{Wiring: []rune("BCDA"), Reversed: true, Position: 'B'}, []rune("ABCD"))
+	for x := 0; x < 4; x++ {
+		if reversed.out(reversed.in(x)) != x {
+			t.Errorf("Reversed rotor does not invert itself at %d", x)
+		}
+	}
+	if reversed.advance(); reversed.position != 0 {
+		t.Errorf("Reversed rotor did not step backwards: %d", reversed.position)
+	}
+
+	enigma := enigmaRotorMachine(NewKeyEnigma("B", []string{"II", "IV", "V"}, []rune("BUL"), []rune("BLA"), []rune("AV BS CG DL FU HZ IN KM OW RX")))
+	if text, _ := EncryptRotorMachine(enigma, []rune("EDPUDNRGYS")); string(text) != "AUFKLXABTE" {
+		errorTest(t, "Rotor machine does not run the Enigma", "AUFKLXABTE", string(text))
+	}
+
+	random := NewSeededRandom(utils.SeedRand())
+	for _, test := range tests {
+		for _, reflector := range [...]bool{false, true} {
+			key := must(random.KeyRotorMachine([]rune(AlphabetL36), 4, reflector))(t)
+			c := NewRotorMachine([]rune(test), key)
+			if !c.Verify() {
+				t.Fatalf("Random rotor machine did not verify: %v", c.GetErrors())
+			}
+
+			c.Encrypt()
+			if string(c.GetText()) == test {
+				t.Errorf("Rotor machine left %s unchanged", test)
+			}
+
+			if c.Decrypt(); string(c.GetText()) != test {
+				errorTest(t, "Rotor machine round trip failed", test, string(c.GetText()))
+			}
+		}
+	}
+}
+
+func testSIGABA(t *testing.T) {
+	key := NewKeySIGABA([]string{"0", "1R", "2", "3R", "4"}, []string{"5", "6R", "7", "8", "9R"}, []string{"0", "1", "2", "3", "4"},
+		[]rune("ABCDE"), []rune("FGHIJ"), []rune("01234"))
+
+	m := newRotorMachine(sigabaRotorMachine(key))
+	for i := 0; i < 500; i++ {
+		before := make([]int, len(m.rotors))
+		for j, r := range m.rotors {
+			before[j] = r.position
+		}
+
+		m.step(m)
+		moved := 0
+		for j, r := range m.rotors {
+			if r.position != before[j] {
+				moved++
+			}
+		}
+
+		if moved < 1 || moved > 4 {
+			t.Fatalf("SIGABA moved %d cipher rotors at step %d", moved, i)
+		}
+	}
+
+	// Not a published vector. It was computed apart from this package by a short model of the
+	// machine written from Stamp and Chan's description, with the index outputs paired 1-2, 3-4,
+	// 5-6, 7-8 and 9-0, and has yet to be checked against an independent SIGABA simulator.
+	c := NewSIGABA([]rune("AAAAAAAAAAAAAAAAAAAAAAAAA"), key)
+	testCipher(t, c, "FJQJMQCWUPNQLUZEGTVOFIBVA", "AAAAAAAAAAAAAAAAAAAAAAAAA")
+
+	for _, test := range tests {
+		test = ToAlpha(test)
+		c := NewSIGABA([]rune(test), key)
+		c.Encrypt()
+		if string(c.GetText()) == test {
+			t.Errorf("SIGABA left %s unchanged", test)
+		}
+
+		if c.Decrypt(); string(c.GetText()) != test {
+			errorTest(t, "SIGABA round trip failed", test, string(c.GetText()))
+		}
+	}
+
+	for name, wiring := range sigabaRotors {
+		if len(verifyPermutation(name, []rune(wiring), []rune(AlphabetL))) > 0 {
+			t.Errorf("SIGABA rotor %s is not a permutation", name)
+		}
+	}
+
+	for name, wiring := range sigabaIndexRotors {
+		if len(verifyPermutation(name, []rune(wiring), []rune("0123456789"))) > 0 {
+			t.Errorf("SIGABA index rotor %s is not a permutation", name)
+		}
+	}
+}
+
+var m209Pins = []string{"ABDHIKMNSTVW", "ADEGJKLORSUX", "ABGHJLMNRSTUX", "CEFHIMNPSTU", "BDEFHIMNPS", "ABDHKNOQ"}
+var m209Lugs = []string{"3-6", "0-6", "1-6", "1-5", "4-5", "0-4", "0-4", "0-4", "0-4", "2-0", "2-0", "2-0", "2-0", "2-0",
+	"2-0", "2-0", "2-0", "2-0", "2-0", "2-5", "2-5", "0-5", "0-5", "0-5", "0-5", "0-5", "0-5"}
+
+func testM209(t *testing.T) {
+	lugs, err := ParseM209Lugs(m209Lugs)
+	if err != nil {
+		t.Fatalf("Lugs were not parsed: %v", err)
+	}
+
+	// Check from the technical manual
+	key := NewKeyM209(ParseM209Pins(m209Pins), lugs, []rune("AAAAAA"))
+	testCipher(t, NewM209([]rune("AAAAAAAAAAAAAAAAAAAAAAAAAA"), key), "TNJUW AUQTK CZKNU TOTBC WARMI O", "AAAAAAAAAAAAAAAAAAAAAAAAAA")
+
+	for _, test := range [...]string{"WE ARE DISCOVERED", "FLEE AT ONCE", "ATTACK AT DAWN"} {
+		c := NewM209([]rune(test), NewKeyM209(ParseM209Pins(m209Pins), lugs, []rune("QKRDEL")))
+		c.Encrypt()
+		if ciphertext := string(c.GetText()); len(ToAlpha(ciphertext)) != len(test) || ciphertext[5] != ' ' {
+			t.Errorf("M-209 ciphertext %s is not in groups of 5 letters for %s", ciphertext, test)
+		}
+
+		if c.Decrypt(); string(c.GetText()) != test {
+			errorTest(t, "M-209 round trip failed", test, string(c.GetText()))
+		}
+	}
+
+	// Without lugs the print wheel never shifts and A becomes Z, with every bar active it shifts a full turn plus one.
+	empty := NewKeyM209(ParseM209Pins([]string{"-", "-", "-", "-", "-", "-"}), make([][]int, 27), []rune("AAAAAA"))
+	if text, _ := EncryptM209(empty, []rune("ABC")); string(text) != "ZYX" {
+		errorTest(t, "M-209 without lugs failed", "ZYX", string(text))
+	}
+
+	full := NewKeyM209([][]rune{[]rune(m209Wheels[0]), nil, nil, nil, nil, nil}, make([][]int, 27), []rune("AAAAAA"))
+	for i := range full.Lugs {
+		full.Lugs[i] = []int{1, 0}
+	}
+	if text, _ := EncryptM209(full, []rune("ABC")); string(text) != "AZY" {
+		errorTest(t, "M-209 with every bar active failed", "AZY", string(text))
+	}
+
+	if _, err := ParseM209Lugs([]string{"1-X"}); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Lugs 1-X were parsed: %v", err)
+	}
+}
+
+func testSolitaire(t *testing.T) {
+	// Test vectors from Schneier's description
+	testCipher(t, NewSolitaire([]rune("AAAAAAAAAA"), NewKeySolitaire(nil, nil)), "EXKYIZSGEH", "AAAAAAAAAA")
+	testCipher(t, NewSolitaire([]rune("AAAAAAAAAAAAAAA"), NewKeySolitaire(nil, []rune("FOO"))), "ITHZUJIWGRFARMW", "AAAAAAAAAAAAAAA")
+	testCipher(t, NewSolitaire([]rune("SOLITAIREX"), NewKeySolitaire(nil, []rune("CRYPTONOMICON"))), "KIRAKSFJAN", "SOLITAIREX")
+
+	// The same deck written with card names and numbers
+	names := make([]string, SolitaireJokerB)
+	for i := range names[:52] {
+		names[i] = solitaireRanks[i % 13] + string(solitaireSuits[i / 13])
+	}
+	names[52], names[53] = "A", "B"
+	deck, err := ParseSolitaireDeck(names)
+	if err != nil {
+		t.Fatalf("Deck was not parsed: %v", err)
+	}
+	testCipher(t, NewSolitaire([]rune("AAAAAAAAAA"), NewKeySolitaire(deck, nil)), "EXKYIZSGEH", "AAAAAAAAAA")
+
+	if deck, _ := ParseSolitaireDeck([]string{"10H", "KS", "17", "B"}); !reflect.DeepEqual(deck, []int{36, 52, 17, 54}) {
+		t.Errorf("Deck 10H KS 17 B was parsed as %v", deck)
+	}
+
+	if _, err := ParseSolitaireDeck([]string{"1C"}); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Card 1C was parsed: %v", err)
+	}
+
+	// Reversing the deck with the jokers on top gives another keystream that still round trips.
+	for i := range deck {
+		deck[i] = SolitaireJokerB - i
+	}
+	for _, test := range [...]string{"WEAREDISCOVERED", "FLEEATONCE"} {
+		c := NewSolitaire([]rune(test), NewKeySolitaire(deck, []rune("SECRET")))
+		c.Encrypt()
+		if string(c.GetText()) == test {
+			t.Errorf("Solitaire did not change %s", test)
+		}
+		if c.Decrypt(); string(c.GetText()) != test {
+			errorTest(t, "Solitaire round trip failed", test, string(c.GetText()))
+		}
+	}
+}
+
 func testAutokey(t *testing.T) {
 	primers := [...]string{"ALLO", "SALUT", "BONJOUR", "COMMENT", "CAVA"}
 	alphabets := [...]string{
@@ -520,6 +975,12 @@ func testChaocipher(t *testing.T) {
 		c := NewChaocipher([]rune(test), NewKeyChaocipher([]rune(lefts[i]), []rune(rights[i])))
 		testCipher(t, c, expects[i], test)
 	}
+
+	c := NewChaocipher([]rune("HELLO WORLD"), NewKeyChaocipher([]rune(lefts[0]), []rune(rights[0])))
+	var rerr *InvalidRuneError
+	if c.Encrypt(); len(c.GetErrors()) != 1 || !errors.As(c.GetErrors()[0], &rerr) || rerr.Position != 5 || string(c.GetText()) != "HELLO WORLD" {
+		t.Errorf("Chaocipher did not record the space as an error: %s, %v", string(c.GetText()), c.GetErrors())
+	}
 }
 
 func testHill(t *testing.T) {
@@ -538,6 +999,45 @@ func testHill(t *testing.T) {
 		c := NewHill([]rune(test), NewKeyHill([]rune(alphabets[i]), mats[i]))
 		testCipherRegex(t, c, expects[i], expectsAfter[i])
 	}
+
+	m := mat.NewDense(5, 5, []float64{
+		11, 2, 7, 19, 4,
+		3, 25, 14, 8, 1,
+		6, 9, 13, 22, 17,
+		20, 5, 0, 12, 15,
+		24, 16, 10, 3, 21,
+	})
+	for n, det := range map[int]int{26: 9, 36: 5} {
+		if res := utils.ModDeterminant(m, n); res != det {
+			t.Errorf("Matrix determinant modulo %d is %d, expected %d", n, res, det)
+		}
+
+		inv, err := utils.ModInverseMatrix(m, n)
+		if err != nil {
+			t.Fatalf("Matrix inverse modulo %d failed: %v", n, err)
+		}
+
+		var id mat.Dense
+		id.Mul(m, inv)
+		id.Apply(func(i, j int, v float64) float64 { return float64(utils.Mod(int(v), n)) }, &id)
+		if !mat.Equal(&id, mat.NewDiagDense(5, []float64{1, 1, 1, 1, 1})) {
+			t.Errorf("Matrix inverse modulo %d is not exact: %v", n, mat.Formatted(inv))
+		}
+	}
+
+	key := NewKeyHill([]rune(AlphabetL), mat.NewDense(2, 2, []float64{2, 4, 1, 3}))
+	if _, err := utils.ModInverseMatrix(key.Matrix, 26); !errors.Is(err, utils.ErrNotInvertible) {
+		t.Errorf("Matrix with determinant 2 was inverted modulo 26: %v", err)
+	}
+
+	c := NewHill([]rune("ATTACK"), key)
+	if c.Decrypt(); len(c.GetErrors()) != 1 || string(c.GetText()) != "ATTACK" {
+		t.Errorf("Hill decrypted with a matrix which is not invertible: %s, %v", string(c.GetText()), c.GetErrors())
+	}
+
+	if err := c.DecryptE(); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Hill did not reject a matrix which is not invertible: %v", err)
+	}
 }
 
 func testTwoSquareV(t *testing.T) {
@@ -630,4 +1130,870 @@ func testFourSquare(t *testing.T) {
 		c := NewFourSquare(ptest, NewKeyFourSquare([]rune(a1[i]), []rune(a2[i]), []rune(a3[i]), []rune(a4[i])))
 		testCipherRegex(t, c, expects[i], string(ptest))
 	}
-}
\ No newline at end of file
+}
+
+func testVerify(t *testing.T) {
+	invalids := [...]ICipherClassical{
+		NewSubstitute(nil, NewKeySubstitute([]rune(AlphabetL), []rune("ABC"))),
+		NewShiftAlphabet(nil, NewKeyShiftAlphabet([]rune("ABCA"), 1)),
+		NewAffine(nil, NewKeyAffine([]rune(AlphabetL), 13, 1)),
+		NewChaocipher(nil, NewKeyChaocipher([]rune(AlphabetL), []rune(AlphabetL36))),
+		NewVigenere(nil, NewKeyVigenere([]rune(AlphabetL), []rune("lemon"))),
+		NewVigenereGronsfeld(nil, NewKeyVigenere([]rune("ABCDE"), []rune("1234567"))),
+		NewAutokey(nil, NewKeyAutokey([]rune(AlphabetL), nil)),
+		NewPolybius(nil, NewKeyPolybius([]rune(AlphabetL36), []rune("ABCDE"))),
+		NewADFGX(nil, NewKeyADFGX([]rune(AlphabetL), []rune("KEY"))),
+		NewADFGVX(nil, NewKeyADFGVX([]rune(AlphabetL36), nil)),
+		NewBifid(nil, NewKeyBifid([]rune(AlphabetL), 5)),
+		NewBifid(nil, NewKeyBifid([]rune(AlphabetL25), -1)),
+		NewTrifid(nil, NewKeyTrifid([]rune(AlphabetL), 5)),
+		NewNihilist(nil, NewKeyNihilist([]rune(AlphabetL25), []rune("ABCDE"), []rune("KEY"))),
+		NewNihilistTransposition(nil, NewKeyNihilistTransposition(nil, false)),
+		NewEnigma(nil, NewKeyEnigma("B", []string{"I", "II", "II"}, []rune("AAA"), []rune("AAA"), nil)),
+		NewEnigma(nil, NewKeyEnigma("B", []string{"Beta", "I", "II", "III"}, []rune("AAAA"), []rune("AAAA"), nil)),
+		NewEnigma(nil, NewKeyEnigma("B-thin", []string{"I", "Beta", "II", "III"}, []rune("AAAA"), []rune("AAAA"), nil)),
+		NewEnigma(nil, NewKeyEnigma("B", []string{"I", "II", "III"}, []rune("AAA"), []rune("AAA"), []rune("AB CA"))),
+		NewEnigma(nil, NewKeyEnigma("B", []string{"I", "II", "III"}, []rune("AA"), []rune("AAA"), []rune("ABC"))),
+		NewRotorMachine(nil, NewKeyRotorMachine([]rune("ABCD"), nil, []KeyRotor{{Wiring: []rune("ABCA"), Position: 'A'}}, nil, SteppingOdometer)),
+		NewRotorMachine(nil, NewKeyRotorMachine([]rune("ABCD"), nil, []KeyRotor{{Wiring: []rune("ABCD"), Position: 'A'}}, []rune("BACD"), SteppingOdometer)),
+		NewRotorMachine(nil, NewKeyRotorMachine([]rune("ABCD"), nil, []KeyRotor{{Wiring: []rune("ABCD"), Position: 'A'}}, nil, SteppingEnigma)),
+		NewRotorMachine(nil, NewKeyRotorMachine([]rune("ABCD"), nil, nil, nil, "hebern")),
+		NewM209(nil, NewKeyM209(ParseM209Pins([]string{"-", "-", "Y", "-", "-", "-"}), make([][]int, 27), []rune("AAAAAA"))),
+		NewM209(nil, NewKeyM209(ParseM209Pins([]string{"-", "W", "-", "-", "-", "-"}), make([][]int, 27), []rune("AAAAAA"))),
+		NewM209(nil, NewKeyM209(ParseM209Pins([]string{"-", "-", "-", "-", "-", "-"}), [][]int{{1, 1}}, []rune("AAAAAZ"))),
+		NewSolitaire(nil, NewKeySolitaire([]int{1, 2, 3}, nil)),
+		NewSolitaire(nil, NewKeySolitaire(nil, []rune("PASS WORD"))),
+		NewSIGABA(nil, NewKeySIGABA([]string{"0", "1", "2", "3", "4"}, []string{"5", "6", "7", "8", "4R"}, []string{"0", "1", "2", "3", "4"}, []rune("AAAAA"), []rune("AAAAA"), []rune("00000"))),
+		NewSIGABA(nil, NewKeySIGABA([]string{"0", "1", "2", "3", "4"}, []string{"5", "6", "7", "8", "9"}, []string{"0", "1", "2", "3", "5"}, []rune("AAAAA"), []rune("AAAAA"), []rune("0000A"))),
+		NewCheckerboard(nil, NewKeyCheckerboard([]rune("ETAONRISBC"), []rune("0123456789"), 0)),
+		NewCheckerboard(nil, NewKeyCheckerboard([]rune(CheckerboardDefault), []rune("0123456789"), '#')),
+		NewVIC(nil, NewKeyVIC([]rune("SHORT"), []rune("391742"), 6, []rune("77651"), []rune(CheckerboardDefault), '/')),
+		NewVIC(nil, NewKeyVIC([]rune("TWASTHENIGHTBEFORECHRISTMAS"), []rune("3917"), 20, []rune("7765X"), []rune(CheckerboardDefault), '/')),
+		NewColumn(nil, NewKeyColumn(nil)),
+		NewColumnDCount(nil, NewKeyColumnDCount([]rune("KEY"), nil)),
+		NewZigzag(nil, NewKeyZigzag(0)),
+		NewScytale(nil, NewKeyScytale(-1)),
+		NewRouteSpiral(nil, NewKeyRoute(3, route{topleft, right, right})),
+		NewPlayfair(nil, NewKeyPlayfair([]rune(AlphabetL), 'X')),
+		NewPlayfair(nil, NewKeyPlayfair([]rune(AlphabetL25), 'J')),
+		NewTwoSquareV(nil, NewKeyTwoSquareV([]rune(AlphabetL25), []rune(AlphabetL36), false)),
+		NewFourSquare(nil, NewKeyFourSquare([]rune(AlphabetL25), []rune(AlphabetL25), []rune(AlphabetL25), []rune(AlphabetL))),
+		NewHill(nil, NewKeyHill([]rune(AlphabetL), mat.NewDense(2, 2, []float64{2, 4, 6, 8}))),
+		NewHill(nil, NewKeyHill([]rune(AlphabetL), mat.NewDense(2, 3, []float64{1, 2, 3, 4, 5, 6}))),
+		NewHill(nil, nil),
+	}
+
+	for i, c := range invalids {
+		if c.Verify() {
+			t.Errorf("Verify %d succeeded on an invalid key", i)
+		} else if len(c.GetErrors()) == 0 || !errors.Is(c.GetErrors()[0], ErrInvalidKey) {
+			t.Errorf("Verify %d did not report an invalid key error: %v", i, c.GetErrors())
+		}
+
+		errs := len(c.GetErrors())
+		if c.Verify(); len(c.GetErrors()) != errs {
+			t.Errorf("Verify %d kept the errors of the previous verification: %v", i, c.GetErrors())
+		}
+	}
+}
+
+func testCryptErrors(t *testing.T) {
+	invalids := [...]struct {
+		c ICipherClassical
+		encrypt bool
+		position int
+		r rune
+	}{
+		{NewVigenere([]rune("HELLO WORLD"), NewKeyVigenere([]rune(AlphabetL), []rune("KEY"))), true, 5, ' '},
+		{NewSubstitute([]rune("ABCZ"), NewKeySubstitute([]rune("ABCD"), []rune("WXYZ"))), false, 0, 'A'},
+		{NewChaocipher([]rune("WELL_DONE"), NewKeyChaocipher([]rune(AlphabetL), RandomAlphabetL())), true, 4, '_'},
+		{NewChaocipher([]rune("abc"), NewKeyChaocipher([]rune(AlphabetL), RandomAlphabetL())), false, 0, 'a'},
+		{NewAffine([]rune("AFFINE1"), NewKeyAffine([]rune(AlphabetL), 5, 8)), true, 6, '1'},
+		{NewPolybius([]rune("1267"), NewKeyPolybius([]rune(AlphabetL36), []rune("123456"))), false, 3, '7'},
+		{NewPlayfair([]rune("JAZZ"), NewKeyPlayfair([]rune(AlphabetL25), 'X')), true, 0, 'J'},
+		{NewFourSquare([]rune("ABJA"), NewKeyFourSquare([]rune(AlphabetL25), RandomAlphabetL25(), RandomAlphabetL25(), []rune(AlphabetL25))), true, 2, 'J'},
+		{NewCheckerboard([]rune("02X"), NewKeyCheckerboard([]rune(CheckerboardDefault), []rune("0123456789"), 0)), false, 1, '2'},
+	}
+
+	for i, test := range invalids {
+		text := string(test.c.GetText())
+		var err error
+		if test.encrypt {
+			err = test.c.EncryptE()
+		} else {
+			err = test.c.DecryptE()
+		}
+
+		var rerr *InvalidRuneError
+		if !errors.As(err, &rerr) || !errors.Is(err, ErrInvalidText) {
+			t.Errorf("Crypt %d did not return an invalid rune error: %v", i, err)
+		} else if rerr.Position != test.position || rerr.Rune != test.r {
+			t.Errorf("Crypt %d reported %q at %d, expected %q at %d", i, rerr.Rune, rerr.Position, test.r, test.position)
+		}
+
+		if string(test.c.GetText()) != text {
+			t.Errorf("Crypt %d modified the text on error", i)
+		}
+	}
+
+	var lerr *InvalidLengthError
+	if err := NewADFGVX([]rune("ADFGV"), NewKeyADFGVX([]rune(AlphabetL36), []rune("KEY"))).DecryptE(); !errors.As(err, &lerr) {
+		t.Errorf("Odd length ADFGVX decrypt did not return a length error: %v", err)
+	}
+
+	if err := NewTwoSquareH([]rune("ABC"), NewKeyTwoSquareH([]rune(AlphabetL25), []rune(AlphabetL25), false)).EncryptE(); !errors.As(err, &lerr) {
+		t.Errorf("Odd length two-square encrypt did not return a length error: %v", err)
+	}
+
+	// The plain methods must not crash on the texts the E methods reject.
+	for _, info := range Ciphers() {
+		for _, text := range [...]string{"HELLO WORLD_~é", "12 x!"} {
+			c, _ := NewCipher(info.Name, []rune(text), registryParams[info.Name])
+			c.Encrypt()
+			c.SetText([]rune(text))
+			c.Decrypt()
+		}
+	}
+
+	if err := NewHill([]rune("HILL"), NewKeyHill([]rune(AlphabetL), mat.NewDense(2, 2, []float64{2, 4, 6, 8}))).EncryptE(); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Hill with a singular matrix did not return a key error: %v", err)
+	}
+
+	if err := NewColumn([]rune("COLUMN"), nil).EncryptE(); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Column without key did not return a key error: %v", err)
+	}
+}
+
+func testPolicy(t *testing.T) {
+	text := "Attack at dawn!"
+	key := NewKeyVigenere([]rune(AlphabetL), []rune("LEMON"))
+	policies := [...]*Policy{
+		NewPolicy(PolicyPassthrough, true, true),
+		NewPolicy(PolicyPassthrough, true, false),
+		NewPolicy(PolicyDrop, true, false),
+		NewPolicy(PolicyDrop, false, false),
+		NewPolicy(PolicyPassthrough, false, true),
+	}
+	expects := [...]string{"Lxfopv ef rnhr!", "Lxfopv mh oeib!", "Lxfopvefrnhr", "L", "Lttack at dawn!"}
+	dexpects := [...]string{text, text, "Attackatdawn", "A", text}
+
+	for i, p := range policies {
+		c := NewVigenere([]rune(text), key)
+		c.Cipher.Policy = p
+		testCipher(t, c, expects[i], dexpects[i])
+	}
+
+	c := NewVigenere([]rune(text), key)
+	c.Cipher.Policy = NewPolicy(PolicyReject, true, false)
+	var rerr *InvalidRuneError
+	if err := c.EncryptE(); !errors.As(err, &rerr) || rerr.Position != 6 {
+		t.Errorf("Reject policy did not reject the space at position 6: %v", err)
+	}
+
+	c.Encrypt()
+	if string(c.GetText()) != text || len(c.GetErrors()) != 1 {
+		t.Errorf("Reject policy modified the text or did not record the error: %s, %v", string(c.GetText()), c.GetErrors())
+	}
+
+	for _, p := range [...]*Policy{NewPolicy(PolicyPassthrough, true, true), NewPolicy(PolicyPassthrough, true, false)} {
+		ciphers := [...]ICipherClassical{
+			&Substitute{Cipher: &CipherClassical[KeySubstitute]{Text: []rune(text), Key: NewKeySubstitute([]rune(AlphabetL), RandomAlphabetL()), Policy: p}},
+			&ShiftAlphabet{Cipher: &CipherClassical[KeyShiftAlphabet]{Text: []rune(text), Key: NewKeyShiftAlphabet([]rune(AlphabetL), 7), Policy: p}},
+			&Affine{Cipher: &CipherClassical[KeyAffine]{Text: []rune(text), Key: NewKeyAffine([]rune(AlphabetL), 5, 8), Policy: p}},
+			&Atbash{Cipher: &CipherClassical[KeyAtbash]{Text: []rune(text), Key: NewKeyAtbash([]rune(AlphabetL)), Policy: p}},
+			&Chaocipher{Cipher: &CipherClassical[KeyChaocipher]{Text: []rune(text), Key: NewKeyChaocipher(RandomAlphabetL(), RandomAlphabetL()), Policy: p}},
+			&VigenereBeaufort{Cipher: &CipherClassical[KeyVigenere]{Text: []rune(text), Key: key, Policy: p}},
+			&VigenereGronsfeld{Cipher: &CipherClassical[KeyVigenere]{Text: []rune(text), Key: NewKeyVigenere([]rune(AlphabetL), []rune("31415")), Policy: p}},
+			&Autokey{Cipher: &CipherClassical[KeyAutokey]{Text: []rune(text), Key: NewKeyAutokey([]rune(AlphabetL), []rune("QUEEN")), Policy: p}},
+			&Beaufort{Cipher: &CipherClassical[KeyBeaufort]{Text: []rune(text), Key: NewKeyBeaufort([]rune(AlphabetL), []rune("FORT")), Policy: p}},
+		}
+
+		for _, c := range ciphers {
+			testCipherRegex(t, c, "^[A-Z][a-z]{5} [a-z]{2} [a-z]{4}!$", "^" + text + "$")
+		}
+	}
+
+	pf := NewPlayfair([]rune("Hide the golds"), NewKeyPlayfair(AlphabetKeyL25([]rune("PLAYFAIR")), 'X'))
+	pf.Cipher.Policy = NewPolicy(PolicyPassthrough, true, true)
+	testCipher(t, pf, "Ebim qmg hvrct", "Hide the golds")
+
+	// The nulls splitting LL and completing the last pair move the runes after them, the case and
+	// the punctuation follow the runes they were on or after.
+	pkey := NewKeyPlayfair(AlphabetKeyL25([]rune("PLAYFAIR")), 'X')
+	e, _ := EncryptPlayfair(pkey, []rune("HELLOWORLD"))
+	lower := func(rs []rune) string { return strings.ToLower(string(rs)) }
+	pf = NewPlayfair([]rune("Hello, world!"), pkey)
+	pf.Cipher.Policy = NewPolicy(PolicyPassthrough, true, false)
+	exp := string(e[0]) + lower(e[1:3]) + string(e[3]) + lower(e[4:6]) + ", " + lower(e[6:11]) + string(e[11]) + "!"
+	testCipher(t, pf, exp, "HelXlo, worldX!")
+	if string(e) != "KGYVRVVQGRCZ" {
+		t.Errorf("Playfair nulls were not inserted: %s", string(e))
+	}
+
+	// A doubled last pair keeps its last rune.
+	e, _ = EncryptPlayfair(pkey, []rune("BALL"))
+	if d, _ := DecryptPlayfair(pkey, e); string(d) != "BALXLX" {
+		t.Errorf("Playfair BALL decrypted to %s", string(d))
+	}
+}
+
+var registryParams = map[string]Params{
+	"substitute": {"sAlphabet": "QWERTYUIOPASDFGHJKLZXCVBNM"},
+	"shift": {"shift": 4},
+	"shift-alphabet": {"shift": -3},
+	"caesar": {"shift": 7.0},
+	"rot13": {},
+	"affine": {"a": 5, "b": "8"},
+	"atbash": {},
+	"chaocipher": {"left": "HXUCZVAMDSLKPEFJRIGTWOBNYQ", "right": "PTLNBQDEOYSFAVZKGJRIHWXUMC"},
+	"vigenere": {"key": "LEMON"},
+	"vigenere-beaufort": {"key": []rune("LEMON")},
+	"vigenere-gronsfeld": {"key": "31415"},
+	"autokey": {"primer": "QUEEN"},
+	"beaufort": {"key": "FORTIFICATION"},
+	"polybius": {"header": "ABCDE"},
+	"adfgx": {"alphabet": "BTALPDHOZKQFVSNGICUXMREWY", "key": "CARGO"},
+	"adfgvx": {"key": "PRIVACY"},
+	"nihilist": {"alphabet": "ZEBRASCDFGHIKLMNOPQTUVWXY", "key": "RUSSIAN"},
+	"nihilist-transposition": {"key": "RUSSIA", "byColumns": true},
+	"checkerboard": {"escape": "/"},
+	"vic": {"phrase": "TWASTHENIGHTBEFORECHRISTMAS", "date": "391742", "personal": 6, "indicator": "77651"},
+	"enigma": {"rotors": []string{"II", "IV", "V"}, "rings": "BUL", "positions": "BLA", "plugboard": "AV BS CG DL FU HZ IN KM OW RX"},
+	"rotor-machine": {"rotors": []KeyRotor{{Wiring: []rune("EKMFLGDQVZNTOWYHXUSPAIBRCJ"), Position: 'C'}, {Wiring: []rune("BDFHJLCPRTXVZNYEIWGAKMUSQO"), Notches: []rune("V"), Position: 'U'}}},
+	"sigaba": {"cipher": []string{"0", "1R", "2", "3R", "4"}, "control": "5 6R 7 8 9R", "cipherPositions": "ABCDE", "controlPositions": "FGHIJ", "indexPositions": "01234"},
+	"m209": {"pins": m209Pins, "lugs": m209Lugs, "positions": "QKRDEL"},
+	"solitaire": {"deck": "A B AC 2C 3C 4C 5C 6C 7C 8C 9C 10C JC QC KC AD 2D 3D 4D 5D 6D 7D 8D 9D 10D JD QD KD AH 2H 3H 4H 5H 6H 7H 8H 9H 10H JH QH KH AS 2S 3S 4S 5S 6S 7S 8S 9S 10S JS QS KS", "passphrase": "CRYPTONOMICON"},
+	"bifid": {"alphabet": "BGWKZQPNDSIOAXEFCLUMTHYVR", "period": 7},
+	"trifid": {"alphabet": "FELIXMARDSTBCGHJKNOPQUVWYZ+", "period": 5},
+	"column": {"key": "ZEBRAS"},
+	"myszkowski": {"key": "TOMATO"},
+	"column-dcount": {"key": "CRYPTO", "dKey": "SECRET"},
+	"column-dline": {"key": "BIRTHDAY", "fill": "true"},
+	"reverse": {},
+	"zigzag": {"lines": 4},
+	"scytale": {"lines": 5},
+	"route-spiral": {"width": 6, "route": "BRU"},
+	"route-serpent": {"width": 5, "route": ROUTE_TRD},
+	"magnet": {},
+	"elastic": {},
+	"playfair": {"alphabet": "PLAYFIREXMBCDGHKNOQSTUVWZ", "null": "X"},
+	"two-square-v": {"alphabet1": "EXAMPLBCDFGHIKNOQRSTUVWYZ", "alphabet2": "KEYWORDABCFGHILMNPQSTUVXZ"},
+	"two-square-h": {"alphabet1": "EXAMPLBCDFGHIKNOQRSTUVWYZ", "alphabet2": "KEYWORDABCFGHILMNPQSTUVXZ", "transparent": true},
+	"four-square": {"alphabet2": "EXAMPLBCDFGHIKNOQRSTUVWYZ", "alphabet3": "KEYWORDABCFGHILMNPQSTUVXZ"},
+	"hill": {"matrix": [][]int{{3, 3}, {2, 5}}},
+}
+
+func testRegistry(t *testing.T) {
+	text := "DEFENDTHEWESTWALLOFTHECASTLE"
+	infos := Ciphers()
+
+	if len(infos) != len(registryParams) {
+		t.Errorf("Registry has %d ciphers, expected %d", len(infos), len(registryParams))
+	}
+
+	for _, info := range infos {
+		params, found := registryParams[info.Name]
+		if !found {
+			t.Errorf("No test parameters for %s", info.Name)
+			continue
+		}
+
+		c, err := NewCipher(info.Name, []rune(text), params)
+		if err != nil {
+			t.Errorf("NewCipher %s failed: %s", info.Name, err)
+			continue
+		}
+
+		testCipherRegex(t, c, ".", "^" + text + ".?$")
+
+		if info.Involutive {
+			c.EncryptE()
+			c.EncryptE()
+			if string(c.GetText()) != text {
+				t.Errorf("%s is marked involutive but encrypting twice gives %s", info.Name, string(c.GetText()))
+			}
+		}
+	}
+
+	c, err := NewCipher("vigenere", []rune("ATTACKATDAWN"), Params{"key": "LEMON"})
+	if err != nil {
+		t.Errorf("NewCipher vigenere failed: %s", err)
+	} else {
+		testCipher(t, c, "LXFOPVEFRNHR", "ATTACKATDAWN")
+	}
+
+	if _, err := NewCipher("enigma-x", nil, nil); !errors.Is(err, ErrUnknownCipher) {
+		t.Errorf("Unknown cipher did not return an unknown cipher error: %v", err)
+	}
+
+	if _, err := NewCipher("zigzag", nil, Params{"lines": "many"}); !errors.Is(err, ErrInvalidParam) {
+		t.Errorf("Invalid parameter did not return an invalid parameter error: %v", err)
+	}
+
+	if _, err := NewCipher("affine", nil, Params{"a": 13}); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Invalid key did not return an invalid key error: %v", err)
+	}
+}
+
+func testKeyEncoding(t *testing.T) {
+	text := "DEFENDTHEWESTWALLOFTHECASTLE"
+
+	for _, info := range Ciphers() {
+		key, err := info.NewKey(registryParams[info.Name])
+		if err != nil {
+			t.Errorf("NewKey %s failed: %s", info.Name, err)
+			continue
+		}
+
+		data, err := MarshalKey(info.Name, key)
+		if err != nil {
+			t.Errorf("MarshalKey %s failed: %s", info.Name, err)
+			continue
+		}
+
+		name, key2, err := UnmarshalKey(data)
+		if err != nil || name != info.Name {
+			t.Errorf("UnmarshalKey %s failed: %s, %v", info.Name, data, err)
+			continue
+		}
+
+		if data2, _ := MarshalKey(name, key2); string(data) != string(data2) {
+			t.Errorf("JSON round trip of %s is not stable. Expected: %s\nActual: %s", info.Name, data, data2)
+		}
+
+		s, err := FormatKey(info.Name, key)
+		if err != nil {
+			t.Errorf("FormatKey %s failed: %s", info.Name, err)
+			continue
+		}
+
+		c, err := ParseCipher(s, []rune(text))
+		if err != nil {
+			t.Errorf("ParseCipher %s failed: %s", s, err)
+			continue
+		}
+
+		if s2, _ := FormatKey(info.Name, key2); s != s2 {
+			t.Errorf("Text round trip of %s is not stable. Expected: %s\nActual: %s", info.Name, s, s2)
+		}
+
+		c2, _ := NewCipher(info.Name, []rune(text), registryParams[info.Name])
+		c.Encrypt()
+		c2.Encrypt()
+		if string(c.GetText()) != string(c2.GetText()) {
+			t.Errorf("Decoded %s key encrypts differently: %s != %s", info.Name, string(c.GetText()), string(c2.GetText()))
+		}
+	}
+
+	keys := [...]struct {
+		name string
+		key any
+		json string
+		text string
+	}{
+		{"vigenere", NewKeyVigenere([]rune("ABC"), []rune("CAB")), `{"cipher":"vigenere","key":{"alphabet":"ABC","key":"CAB"}}`, `vigenere:alphabet="ABC";key="CAB"`},
+		{"hill", NewKeyHill([]rune("AB;C="), mat.NewDense(2, 2, []float64{3, 3, 2, 5})), `{"cipher":"hill","key":{"alphabet":"AB;C=","matrix":[[3,3],[2,5]]}}`, `hill:alphabet="AB;C=";matrix=[[3,3],[2,5]]`},
+		{"route-serpent", NewKeyRoute(4, ROUTE_BLU), `{"cipher":"route-serpent","key":{"width":4,"route":"BLU"}}`, `route-serpent:width=4;route="BLU"`},
+		{"playfair", NewKeyPlayfair([]rune(AlphabetL25), 0), `{"cipher":"playfair","key":{"alphabet":"ABCDEFGHIKLMNOPQRSTUVWXYZ","null":""}}`, `playfair:alphabet="ABCDEFGHIKLMNOPQRSTUVWXYZ";null=""`},
+		{"rot13", &KeyNone{}, `{"cipher":"rot13","key":{}}`, `rot13:`},
+	}
+
+	for _, k := range keys {
+		if data, err := MarshalKey(k.name, k.key); err != nil || string(data) != k.json {
+			t.Errorf("MarshalKey %s. Expected: %s\nActual: %s (%v)", k.name, k.json, data, err)
+		}
+
+		if s, err := FormatKey(k.name, k.key); err != nil || s != k.text {
+			t.Errorf("FormatKey %s. Expected: %s\nActual: %s (%v)", k.name, k.text, s, err)
+		}
+
+		if _, key, err := ParseKey(k.text); err != nil || !reflect.DeepEqual(key, k.key) {
+			t.Errorf("ParseKey %s. Expected: %v\nActual: %v (%v)", k.name, k.key, key, err)
+		}
+	}
+
+	if _, err := MarshalKey("vigenere", NewKeyCaesar(3)); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("MarshalKey with a mismatched key did not fail: %v", err)
+	}
+
+	if _, _, err := UnmarshalKey([]byte(`{"cipher":"caesar","key":{"shift":3,"rotation":1}}`)); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("UnmarshalKey with an unknown field did not fail: %v", err)
+	}
+
+	if _, _, err := UnmarshalKey([]byte(`{"cipher":"vigenere","key":{"key":"LEMON"}}`)); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("UnmarshalKey with a missing field did not fail: %v", err)
+	}
+
+	if _, _, err := ParseKey(`enigma:reflector="B";rotors=["I","II","III"]`); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("ParseKey with missing fields did not fail: %v", err)
+	}
+
+	if _, _, err := ParseKey(`caesar:shift=3;`); err == nil {
+		t.Errorf("ParseKey with a trailing separator did not fail")
+	}
+}
+
+func testPipeline(t *testing.T) {
+	for _, test := range tests {
+		vigenere := NewKeyVigenere([]rune(AlphabetL36), []rune("LEMON"))
+		column := NewKeyColumn([]rune("ZEBRAS"))
+		expect := NewColumn(cryptVigenere([]rune(test), vigenere.Alphabet, vigenere.Key, true), column)
+		expect.Encrypt()
+
+		c := NewPipeline([]rune(test), NewVigenere(nil, vigenere), NewColumn(nil, column), NewColumnDLine(nil, NewKeyColumnDLine([]rune("HORSE"), false)))
+		c2 := NewColumnDLine(expect.GetText(), NewKeyColumnDLine([]rune("HORSE"), false))
+		c2.Encrypt()
+		testCipher(t, c, string(c2.GetText()), test)
+	}
+
+	c := NewPipeline([]rune("DEFENDTHEWESTWALL"),
+		NewPlayfair(nil, NewKeyPlayfair(AlphabetKeyL25([]rune("PLAYFAIR")), 'X')),
+		NewZigzag(nil, NewKeyZigzag(3)),
+	)
+	testCipherRegex(t, c, "^[A-Z]{18}$", "^DEFENDTHEWESTWALLX$")
+
+	c = NewPipeline([]rune("ATTACK AT DAWN"), NewReverse(nil), NewAffine(nil, NewKeyAffine([]rune(AlphabetL), 5, 8)))
+	var rerr *InvalidRuneError
+	if err := c.EncryptE(); !errors.As(err, &rerr) || !strings.HasPrefix(err.Error(), "stage 1:") || rerr.Position != 4 {
+		t.Errorf("Pipeline did not report the invalid rune of stage 1: %v", err)
+	}
+
+	c = NewPipeline(nil, NewZigzag(nil, NewKeyZigzag(0)), NewAffine(nil, NewKeyAffine([]rune(AlphabetL), 2, 8)))
+	if c.Verify() || len(c.GetErrors()) != 2 || !strings.HasPrefix(c.GetErrors()[1].Error(), "stage 1:") {
+		t.Errorf("Pipeline did not aggregate the errors of its stages: %v", c.GetErrors())
+	}
+
+	if NewPipeline(nil).Verify() {
+		t.Errorf("Pipeline without stages verified")
+	}
+}
+
+func streamCiphers() []ICipherClassical {
+	var text []rune
+	return []ICipherClassical{
+		NewCaesar(text, NewKeyCaesar(7)),
+		NewROT13(text),
+		NewShift(text, NewKeyShift(3)),
+		NewShiftAlphabet(text, NewKeyShiftAlphabet([]rune(AlphabetL36), -5)),
+		NewSubstitute(text, NewKeySubstitute([]rune(AlphabetL), []rune("QWERTYUIOPASDFGHJKLZXCVBNM"))),
+		NewAffine(text, NewKeyAffine([]rune(AlphabetL), 5, 8)),
+		NewAtbash(text, NewKeyAtbash([]rune(AlphabetL36))),
+		NewVigenere(text, NewKeyVigenere([]rune(AlphabetL36), []rune("LEMON"))),
+		NewVigenereBeaufort(text, NewKeyVigenere([]rune(AlphabetL36), []rune("LEMON"))),
+		NewVigenereGronsfeld(text, NewKeyVigenere([]rune(AlphabetL36), []rune("31415"))),
+		NewBeaufort(text, NewKeyBeaufort([]rune(AlphabetL36), []rune("FORTIFICATION"))),
+		NewAutokey(text, NewKeyAutokey([]rune(AlphabetL36), []rune("QUEENLY"))),
+		NewChaocipher(text, NewKeyChaocipher([]rune("HXUCZVAMDSLKPEFJRIGTWOBNYQ"), []rune("PTLNBQDEOYSFAVZKGJRIHWXUMC"))),
+	}
+}
+
+// A writer which takes at most n bytes.
+type shortWriter struct {
+	n int
+}
+
+func (w *shortWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		p = p[:w.n]
+	}
+	w.n -= len(p)
+	return len(p), nil
+}
+
+func streamWrite(t *testing.T, c ICipherClassical, input string, encrypt bool) string {
+	var buf bytes.Buffer
+	var w *Writer
+	var err error
+
+	if encrypt {
+		w, err = NewEncryptWriter(&buf, c)
+	} else {
+		w, err = NewDecryptWriter(&buf, c)
+	}
+
+	if err != nil {
+		t.Fatalf("%T could not be streamed: %v", c, err)
+	}
+
+	data := []byte(input)
+	for len(data) > 0 {
+		n := 3
+		if len(data) < n {
+			n = len(data)
+		}
+
+		if _, err := w.Write(data[:n]); err != nil {
+			t.Fatalf("%T stream write failed: %v", c, err)
+		}
+		data = data[n:]
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("%T stream close failed: %v", c, err)
+	}
+
+	return buf.String()
+}
+
+func testStream(t *testing.T) {
+	for _, test := range tests {
+		for _, c := range streamCiphers() {
+			c.SetText([]rune(test))
+			if err := c.EncryptE(); err != nil {
+				r, _ := NewEncryptReader(strings.NewReader(test), c)
+				if _, serr := io.ReadAll(r); serr == nil || serr.Error() != err.Error() {
+					t.Errorf("%T stream error differs from batch: %v, %v", c, serr, err)
+				}
+				continue
+			}
+
+			if res := streamWrite(t, c, test, true); res != string(c.GetText()) {
+				errorTest(t, fmt.Sprintf("%T stream encryption failed", c), string(c.GetText()), res)
+			}
+
+			if res := streamWrite(t, c, string(c.GetText()), false); res != test {
+				errorTest(t, fmt.Sprintf("%T stream decryption failed", c), test, res)
+			}
+		}
+	}
+
+	text := "Attack at dawn! ÉTÉ"
+	for _, p := range [...]*Policy{NewPolicy(PolicyPassthrough, true, false), NewPolicy(PolicyPassthrough, true, true), NewPolicy(PolicyDrop, false, false)} {
+		c := NewVigenere([]rune(text), NewKeyVigenere([]rune(AlphabetL), []rune("LEMON")))
+		c.Cipher.Policy = p
+		c.Encrypt()
+		if res := streamWrite(t, c, text, true); res != string(c.GetText()) {
+			errorTest(t, "Stream policy did not match batch encryption", string(c.GetText()), res)
+		}
+	}
+
+	c := NewChaocipher(nil, NewKeyChaocipher([]rune("HXUCZVAMDSLKPEFJRIGTWOBNYQ"), []rune("PTLNBQDEOYSFAVZKGJRIHWXUMC")))
+	r, err := NewEncryptReader(strings.NewReader(tests[0]), c)
+	if err != nil {
+		t.Fatalf("Chaocipher could not be streamed: %v", err)
+	}
+	r, _ = NewDecryptReader(r, c)
+	if res, err := io.ReadAll(r); err != nil || string(res) != tests[0] {
+		t.Errorf("Stream reader round trip failed: %s, %v", string(res), err)
+	}
+
+	r, _ = NewEncryptReader(strings.NewReader("ATTACK AT DAWN"), NewAffine(nil, NewKeyAffine([]rune(AlphabetL), 5, 8)))
+	var rerr *InvalidRuneError
+	if _, err := io.ReadAll(r); !errors.As(err, &rerr) || rerr.Position != 6 {
+		t.Errorf("Stream reader did not reject the space at position 6: %v", err)
+	}
+
+	var buf bytes.Buffer
+	w, _ := NewEncryptWriter(&buf, NewAffine(nil, NewKeyAffine([]rune(AlphabetL), 5, 8)))
+	w.Write([]byte("\xc3"))
+	if n, err := w.Write([]byte("\x89AB C")); !errors.As(err, &rerr) || n != 0 || buf.Len() != 0 {
+		t.Errorf("Stream writer counted a rejected rune as written: %d, %q, %v", n, buf.String(), err)
+	}
+
+	buf.Reset()
+	w, _ = NewEncryptWriter(&buf, NewAffine(nil, NewKeyAffine([]rune(AlphabetL), 5, 8)))
+	if n, err := w.Write([]byte("AB C")); !errors.As(err, &rerr) || n != 2 || buf.String() != "IN" {
+		t.Errorf("Stream writer did not count the runes before the rejected one: %d, %q, %v", n, buf.String(), err)
+	}
+
+	// The É of 2 bytes is encrypted to a rune of 1 byte.
+	w, _ = NewEncryptWriter(&shortWriter{n: 3}, NewCaesar(nil, NewKeyCaesar(3)))
+	if n, err := w.Write([]byte("AÉBC")); !errors.Is(err, io.ErrShortWrite) || n != 4 {
+		t.Errorf("Stream writer did not count the runes written before a short write: %d, %v", n, err)
+	}
+
+	w, _ = NewEncryptWriter(io.Discard, NewCaesar(nil, NewKeyCaesar(3)))
+	w.Write([]byte("AB\xc3"))
+	if err := w.Close(); !errors.Is(err, ErrInvalidText) {
+		t.Errorf("Stream writer did not report the truncated rune: %v", err)
+	}
+
+	if _, err := NewEncryptWriter(io.Discard, NewColumn(nil, NewKeyColumn([]rune("ZEBRAS")))); !errors.Is(err, ErrNotStreamable) {
+		t.Errorf("Column was accepted as streamable: %v", err)
+	}
+
+	if _, err := NewEncryptWriter(io.Discard, NewAffine(nil, NewKeyAffine([]rune(AlphabetL), 2, 8))); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Stream accepted an invalid key: %v", err)
+	}
+}
+
+func testRandom(t *testing.T) {
+	seed := utils.SeedRand()
+	results := make([]string, 2)
+
+	for i := range results {
+		random := NewSeededRandom(seed)
+		pf := NewPlayfair([]rune("BALLOONS"), NewKeyPlayfair([]rune(AlphabetL25), 0))
+		pf.Cipher.Random = random
+		pf.Encrypt()
+
+		hill := NewHill([]rune("ACT"), NewKeyHill([]rune(AlphabetL), mat.NewDense(2, 2, []float64{3, 3, 2, 5})))
+		hill.Cipher.Random = random
+		hill.Encrypt()
+
+		results[i] = string(random.AlphabetL()) + string(random.AlphabetKeyL25([]rune("KEY"))) + string(pf.GetText()) + string(hill.GetText())
+	}
+
+	if results[0] != results[1] {
+		t.Errorf("Seeded randomness is not reproducible with seed %d: %s, %s", seed, results[0], results[1])
+	}
+
+	if !strings.HasPrefix(string(NewSeededRandom(seed).AlphabetKeyL25([]rune("KEY"))), "KEY") {
+		t.Errorf("Random alphabet key does not start with the key")
+	}
+
+	var random *Random
+	if len(random.Padded([]rune("ABC"), 4)) != 4 {
+		t.Errorf("Nil random did not pad the text")
+	}
+}
+
+func testRandomKeys(t *testing.T) {
+	seed := utils.SeedRand()
+	random := NewSeededRandom(seed)
+	l36 := []rune(AlphabetL36)
+
+	for i := 0; i < 20; i++ {
+		ciphers := []ICipherClassical{
+			NewSubstitute(nil, must(random.KeySubstitute(l36))(t)),
+			NewShift(nil, random.KeyShift()),
+			NewShiftAlphabet(nil, must(random.KeyShiftAlphabet(l36))(t)),
+			NewCaesar(nil, random.KeyCaesar()),
+			NewAffine(nil, must(random.KeyAffine(l36))(t)),
+			NewAtbash(nil, must(random.KeyAtbash(l36))(t)),
+			NewChaocipher(nil, must(random.KeyChaocipher(l36))(t)),
+			NewVigenere(nil, must(random.KeyVigenere(l36, 7))(t)),
+			NewVigenereGronsfeld(nil, must(random.KeyGronsfeld(l36, 5))(t)),
+			NewAutokey(nil, must(random.KeyAutokey(l36, 4))(t)),
+			NewBeaufort(nil, must(random.KeyBeaufort(l36, 6))(t)),
+			NewPolybius(nil, must(random.KeyPolybius(l36, []rune("ABCDEF")))(t)),
+			NewADFGVX(nil, must(random.KeyADFGVX(8))(t)),
+			NewBifid(nil, must(random.KeyBifid(l36, 9))(t)),
+			NewNihilist(nil, must(random.KeyNihilist(l36, []rune("123456"), 5))(t)),
+			NewNihilistTransposition(nil, must(random.KeyNihilistTransposition(l36, 4))(t)),
+			NewCheckerboard(nil, must(random.KeyCheckerboard([]rune(AlphabetL + "/."), 2, '/'))(t)),
+			NewVIC(nil, must(random.KeyVIC([]rune(AlphabetL + "/."), '/'))(t)),
+			NewHill(nil, must(random.KeyHill(l36, 2 + i % 2))(t)),
+			NewColumn(nil, must(random.KeyColumn(l36, 9))(t)),
+			NewMyszkowski(nil, must(random.KeyMyszkowski([]rune(AlphabetL), 8))(t)),
+			NewColumnDCount(nil, must(random.KeyColumnDCount([]rune(AlphabetL), 6, 4))(t)),
+			NewColumnDLine(nil, must(random.KeyColumnDLine([]rune(AlphabetL), 5))(t)),
+			NewZigzag(nil, must(random.KeyZigzag(6))(t)),
+			NewScytale(nil, must(random.KeyScytale(6))(t)),
+			NewRouteSpiral(nil, must(random.KeyRoute(6))(t)),
+			NewRotorMachine(nil, must(random.KeyRotorMachine(l36, 3, i % 2 == 0))(t)),
+		}
+
+		for _, c := range ciphers {
+			for _, test := range tests {
+				c.SetText([]rune(test))
+				if err := c.EncryptE(); err != nil {
+					t.Fatalf("%T random key with seed %d failed to encrypt: %v", c, seed, err)
+				}
+
+				_, padded := c.(*Hill)
+				_, nulled := c.(*VIC)
+				if err := c.DecryptE(); err != nil || string(c.GetText()) != test && !(padded && strings.HasPrefix(string(c.GetText()), test)) && !(nulled && vicNulled(string(c.GetText()), test)) {
+					t.Errorf("%T random key with seed %d did not round trip %s: %s, %v", c, seed, test, string(c.GetText()), err)
+				}
+			}
+		}
+
+		for _, c := range []ICipherClassical{
+			NewPlayfair(nil, must(random.KeyPlayfair([]rune(AlphabetL25)))(t)),
+			NewTwoSquareV(nil, must(random.KeyTwoSquareV([]rune(AlphabetL25)))(t)),
+			NewTwoSquareH(nil, must(random.KeyTwoSquareH([]rune(AlphabetL36)))(t)),
+			NewFourSquare(nil, must(random.KeyFourSquare([]rune(AlphabetL25)))(t)),
+			NewADFGX(nil, must(random.KeyADFGX(6))(t)),
+			NewTrifid(nil, must(random.KeyTrifid('.', 8))(t)),
+			NewEnigma(nil, must(random.KeyEnigma(3 + i % 2, 10))(t)),
+			NewSIGABA(nil, random.KeySIGABA()),
+			NewM209(nil, random.KeyM209()),
+			NewSolitaire(nil, random.KeySolitaire()),
+			NewHill(nil, must(random.KeyHill(l36, 4))(t)),
+		} {
+			if !c.Verify() {
+				t.Errorf("%T random key with seed %d did not verify: %v", c, seed, c.GetErrors())
+			}
+		}
+
+		column := must(random.KeyColumn([]rune(AlphabetL), 26))(t)
+		if len(getSortedKeyIndices(column.Key)) != 26 || string(AlphabetKey([]rune(AlphabetL), column.Key)) != string(column.Key) {
+			t.Errorf("Random column key with seed %d has ties: %s", seed, string(column.Key))
+		}
+
+		myszkowski := must(random.KeyMyszkowski([]rune(AlphabetL), 5))(t)
+		if len(AlphabetKey(myszkowski.Key, myszkowski.Key)) == len(myszkowski.Key) {
+			t.Errorf("Random Myszkowski key with seed %d has no repeats: %s", seed, string(myszkowski.Key))
+		}
+
+		for width := 2; width <= 8; width++ {
+			if key := must(random.KeyMyszkowski([]rune(AlphabetL), width))(t); len(AlphabetKey(key.Key, key.Key)) < 2 {
+				t.Errorf("Random Myszkowski key with seed %d is a single rune: %s", seed, string(key.Key))
+			}
+		}
+	}
+
+	if _, err := random.Keyword([]rune("ABC"), 4); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Keyword longer than its alphabet returned %v", err)
+	}
+	if _, err := random.Checkerboard([]rune(AlphabetL), 2); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Checkerboard of the wrong size returned %v", err)
+	}
+	if _, err := random.KeyMyszkowski([]rune(AlphabetL), 1); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Myszkowski key of width 1 returned %v", err)
+	}
+	if _, err := random.KeyEnigma(3, 14); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Enigma key with 14 plugs returned %v", err)
+	}
+
+	for i, err := range []error{
+		second(random.KeyBifid([]rune(AlphabetL25), 1)),
+		second(random.KeyZigzag(1)),
+		second(random.KeyRoute(0)),
+		second(random.KeyHill([]rune(AlphabetL), 0)),
+		second(random.KeyHill([]rune("A"), 2)),
+		second(random.KeyVigenere(nil, 3)),
+		second(random.KeyVigenere([]rune(AlphabetL), 0)),
+		second(random.KeyRotorMachine([]rune("ABCDE"), 3, true)),
+		second(random.Word(nil, 3)),
+	} {
+		if !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Random key %d with a bad size returned %v", i, err)
+		}
+	}
+}
+
+// Fails the test through t when the key could not be drawn. It takes the results of the generator
+// first and t after, as Go only spreads a call over the whole argument list.
+func must[K any](key *K, err error) func(t *testing.T) *K {
+	return func(t *testing.T) *K {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("Random key failed: %v", err)
+		}
+
+		return key
+	}
+}
+
+func second[T any](_ T, err error) error { return err }
+
+type pureFunc func(key any, text []rune) ([]rune, error)
+
+func pure[K CipherClassicalKey](encrypt, decrypt func(*K, []rune) ([]rune, error)) [2]pureFunc {
+	return [2]pureFunc{
+		func(key any, text []rune) ([]rune, error) { return encrypt(key.(*K), text) },
+		func(key any, text []rune) ([]rune, error) { return decrypt(key.(*K), text) },
+	}
+}
+
+func pureNone(encrypt, decrypt func([]rune) ([]rune, error)) [2]pureFunc {
+	return [2]pureFunc{
+		func(key any, text []rune) ([]rune, error) { return encrypt(text) },
+		func(key any, text []rune) ([]rune, error) { return decrypt(text) },
+	}
+}
+
+var pureCiphers = map[string][2]pureFunc{
+	"substitute": pure(EncryptSubstitute, DecryptSubstitute),
+	"shift": pure(EncryptShift, DecryptShift),
+	"shift-alphabet": pure(EncryptShiftAlphabet, DecryptShiftAlphabet),
+	"caesar": pure(EncryptCaesar, DecryptCaesar),
+	"rot13": pureNone(EncryptROT13, DecryptROT13),
+	"affine": pure(EncryptAffine, DecryptAffine),
+	"atbash": pure(EncryptAtbash, DecryptAtbash),
+	"chaocipher": pure(EncryptChaocipher, DecryptChaocipher),
+	"vigenere": pure(EncryptVigenere, DecryptVigenere),
+	"vigenere-beaufort": pure(EncryptVigenereBeaufort, DecryptVigenereBeaufort),
+	"vigenere-gronsfeld": pure(EncryptVigenereGronsfeld, DecryptVigenereGronsfeld),
+	"autokey": pure(EncryptAutokey, DecryptAutokey),
+	"beaufort": pure(EncryptBeaufort, DecryptBeaufort),
+	"polybius": pure(EncryptPolybius, DecryptPolybius),
+	"adfgx": pure(EncryptADFGX, DecryptADFGX),
+	"adfgvx": pure(EncryptADFGVX, DecryptADFGVX),
+	"nihilist": pure(EncryptNihilist, DecryptNihilist),
+	"nihilist-transposition": pure(EncryptNihilistTransposition, DecryptNihilistTransposition),
+	"checkerboard": pure(EncryptCheckerboard, DecryptCheckerboard),
+	"vic": pure(EncryptVIC, DecryptVIC),
+	"enigma": pure(EncryptEnigma, DecryptEnigma),
+	"rotor-machine": pure(EncryptRotorMachine, DecryptRotorMachine),
+	"sigaba": pure(EncryptSIGABA, DecryptSIGABA),
+	"m209": pure(EncryptM209, DecryptM209),
+	"solitaire": pure(EncryptSolitaire, DecryptSolitaire),
+	"bifid": pure(EncryptBifid, DecryptBifid),
+	"trifid": pure(EncryptTrifid, DecryptTrifid),
+	"column": pure(EncryptColumn, DecryptColumn),
+	"myszkowski": pure(EncryptMyszkowski, DecryptMyszkowski),
+	"column-dcount": pure(EncryptColumnDCount, DecryptColumnDCount),
+	"column-dline": pure(EncryptColumnDLine, DecryptColumnDLine),
+	"reverse": pureNone(EncryptReverse, DecryptReverse),
+	"zigzag": pure(EncryptZigzag, DecryptZigzag),
+	"scytale": pure(EncryptScytale, DecryptScytale),
+	"route-spiral": pure(EncryptRouteSpiral, DecryptRouteSpiral),
+	"route-serpent": pure(EncryptRouteSerpent, DecryptRouteSerpent),
+	"magnet": pureNone(EncryptMagnet, DecryptMagnet),
+	"elastic": pureNone(EncryptElastic, DecryptElastic),
+	"playfair": pure(EncryptPlayfair, DecryptPlayfair),
+	"two-square-v": pure(EncryptTwoSquareV, DecryptTwoSquareV),
+	"two-square-h": pure(EncryptTwoSquareH, DecryptTwoSquareH),
+	"four-square": pure(EncryptFourSquare, DecryptFourSquare),
+	"hill": pure(EncryptHill, DecryptHill),
+}
+
+func testPure(t *testing.T) {
+	text := "DEFENDTHEWESTWALLOFTHECASTLE"
+
+	if len(pureCiphers) != len(Ciphers()) {
+		t.Errorf("Pure functions cover %d ciphers out of %d", len(pureCiphers), len(Ciphers()))
+	}
+
+	for _, info := range Ciphers() {
+		crypt, found := pureCiphers[info.Name]
+		if !found {
+			t.Errorf("No pure functions for %s", info.Name)
+			continue
+		}
+
+		key, _ := info.NewKey(registryParams[info.Name])
+		before, _ := FormatKey(info.Name, key)
+		input := []rune(text)
+
+		result, err := crypt[0](key, input)
+		if err != nil {
+			t.Errorf("Pure encryption of %s failed: %v", info.Name, err)
+			continue
+		}
+
+		again, _ := crypt[0](key, input)
+		if string(again) != string(result) {
+			errorTest(t, fmt.Sprintf("Pure encryption of %s is not repeatable", info.Name), string(result), string(again))
+		}
+
+		c, _ := info.NewCipher([]rune(text), key)
+		if c.Encrypt(); string(c.GetText()) != string(result) {
+			errorTest(t, fmt.Sprintf("Pure encryption of %s differs from %T", info.Name, c), string(c.GetText()), string(result))
+		}
+
+		plain, err := crypt[1](key, append([]rune{}, result...))
+		if err != nil || string(plain) != text {
+			t.Errorf("Pure decryption of %s failed: %s, %v", info.Name, string(plain), err)
+		}
+
+		if after, _ := FormatKey(info.Name, key); string(input) != text || after != before {
+			t.Errorf("Pure functions of %s modified their input: %s, %s", info.Name, string(input), after)
+		}
+	}
+
+	text2 := []rune("ATTACK AT DAWN")
+	if _, err := EncryptAffine(NewKeyAffine([]rune(AlphabetL), 5, 8), text2); !errors.Is(err, ErrInvalidText) || string(text2) != "ATTACK AT DAWN" {
+		t.Errorf("Pure encryption did not report the invalid rune: %v", err)
+	}
+
+	shared := []rune("HELLO")
+	NewCaesar(shared, NewKeyCaesar(3)).Encrypt()
+	NewReverse(shared).Encrypt()
+	if string(shared) != "HELLO" {
+		t.Errorf("Stateful ciphers modified the caller's slice: %s", string(shared))
+	}
+}
@@ -44,6 +44,10 @@ func getSortedKeyPositions(key []rune) []int {
 
 func NewKeyColumn(key []rune) *KeyColumn { return &KeyColumn{Key: key} }
 func NewColumn(text []rune, key *KeyColumn) *Column { return &Column{Cipher: &CipherClassical[KeyColumn]{Text: text, Key: key}} }
+func EncryptColumn(key *KeyColumn, text []rune) ([]rune, error) { return cryptPure(verifyColumn, nil, pureColumn, key, text, true) }
+func DecryptColumn(key *KeyColumn, text []rune) ([]rune, error) { return cryptPure(verifyColumn, nil, pureColumn, key, text, false) }
+
+func pureColumn(k *KeyColumn, text []rune, encrypt bool, _ *Random) ([]rune, error) { return cryptColumn(text, k.Key, encrypt), nil }
 
 type KeyColumn struct { 
 	Key []rune
@@ -52,9 +56,14 @@ type KeyColumn struct {
 type Column struct { Cipher *CipherClassical[KeyColumn] }
 func (c *Column) GetText() []rune { return c.Cipher.Text }
 func (c *Column) GetErrors() []error { return c.Cipher.Errors }
-func (c *Column) Encrypt() { c.Cipher.Text = cryptColumn(c.Cipher.Text, c.Cipher.Key.Key, true) }
-func (c *Column) Decrypt() { c.Cipher.Text = cryptColumn(c.Cipher.Text, c.Cipher.Key.Key, false) }
-func (c *Column) Verify() bool { return true }
+func (c *Column) SetText(text []rune) { c.Cipher.Text = text }
+func (c *Column) Encrypt() { c.Cipher.crypt(pureColumn, true) }
+func (c *Column) Decrypt() { c.Cipher.crypt(pureColumn, false) }
+func (c *Column) EncryptE() error { return c.Cipher.cryptE(verifyColumn, nil, true, c.Encrypt) }
+func (c *Column) DecryptE() error { return c.Cipher.cryptE(verifyColumn, nil, false, c.Decrypt) }
+func (c *Column) Verify() bool { return c.Cipher.verify(verifyColumn) }
+
+func verifyColumn(k *KeyColumn) []error { return collectErrors(verifyNotEmpty("key", k.Key)) }
 
 func cryptColumn(text, key []rune, encrypt bool) []rune {
 	result := make([]rune, len(text))
@@ -78,6 +87,10 @@ func cryptColumn(text, key []rune, encrypt bool) []rune {
 
 func NewKeyMyszkowski(key []rune) *KeyMyszkowski { return &KeyMyszkowski{Key: key} }
 func NewMyszkowski(text []rune, key *KeyMyszkowski) *Myszkowski { return &Myszkowski{Cipher: &CipherClassical[KeyMyszkowski]{Text: text, Key: key}} }
+func EncryptMyszkowski(key *KeyMyszkowski, text []rune) ([]rune, error) { return cryptPure(verifyMyszkowski, nil, pureMyszkowski, key, text, true) }
+func DecryptMyszkowski(key *KeyMyszkowski, text []rune) ([]rune, error) { return cryptPure(verifyMyszkowski, nil, pureMyszkowski, key, text, false) }
+
+func pureMyszkowski(k *KeyMyszkowski, text []rune, encrypt bool, _ *Random) ([]rune, error) { return cryptMyszkowski(text, k.Key, encrypt), nil }
 
 type KeyMyszkowski struct {
 	Key []rune 
@@ -86,9 +99,14 @@ type KeyMyszkowski struct {
 type Myszkowski struct { Cipher *CipherClassical[KeyMyszkowski] }
 func (c *Myszkowski) GetText() []rune { return c.Cipher.Text }
 func (c *Myszkowski) GetErrors() []error { return c.Cipher.Errors }
-func (c *Myszkowski) Encrypt() { c.Cipher.Text = cryptMyszkowski(c.Cipher.Text, c.Cipher.Key.Key, true) }
-func (c *Myszkowski) Decrypt() { c.Cipher.Text = cryptMyszkowski(c.Cipher.Text, c.Cipher.Key.Key, false) }
-func (c *Myszkowski) Verify() bool { return true }
+func (c *Myszkowski) SetText(text []rune) { c.Cipher.Text = text }
+func (c *Myszkowski) Encrypt() { c.Cipher.crypt(pureMyszkowski, true) }
+func (c *Myszkowski) Decrypt() { c.Cipher.crypt(pureMyszkowski, false) }
+func (c *Myszkowski) EncryptE() error { return c.Cipher.cryptE(verifyMyszkowski, nil, true, c.Encrypt) }
+func (c *Myszkowski) DecryptE() error { return c.Cipher.cryptE(verifyMyszkowski, nil, false, c.Decrypt) }
+func (c *Myszkowski) Verify() bool { return c.Cipher.verify(verifyMyszkowski) }
+
+func verifyMyszkowski(k *KeyMyszkowski) []error { return collectErrors(verifyNotEmpty("key", k.Key)) }
 
 func cryptMyszkowski(text, key []rune, encrypt bool) []rune {
 	result := make([]rune, len(text))
@@ -125,6 +143,16 @@ func cryptMyszkowski(text, key []rune, encrypt bool) []rune {
 
 func NewKeyColumnDCount(key, dkey []rune) *KeyColumnDCount { return &KeyColumnDCount{Key: key, DKey: dkey} }
 func NewColumnDCount(text []rune, key *KeyColumnDCount) *ColumnDCount { return &ColumnDCount{Cipher: &CipherClassical[KeyColumnDCount]{Text: text, Key: key}} }
+func EncryptColumnDCount(key *KeyColumnDCount, text []rune) ([]rune, error) { return cryptPure(verifyColumnDCount, nil, pureColumnDCount, key, text, true) }
+func DecryptColumnDCount(key *KeyColumnDCount, text []rune) ([]rune, error) { return cryptPure(verifyColumnDCount, nil, pureColumnDCount, key, text, false) }
+
+func pureColumnDCount(k *KeyColumnDCount, text []rune, encrypt bool, _ *Random) ([]rune, error) {
+	if encrypt {
+		return encryptColumnDCount(text, k.Key, k.DKey), nil
+	}
+
+	return decryptColumnDCount(text, k.Key, k.DKey), nil
+}
 
 type KeyColumnDCount struct {
 	Key []rune
@@ -134,9 +162,19 @@ type KeyColumnDCount struct {
 type ColumnDCount struct { Cipher *CipherClassical[KeyColumnDCount] }
 func (c *ColumnDCount) GetText() []rune { return c.Cipher.Text }
 func (c *ColumnDCount) GetErrors() []error { return c.Cipher.Errors }
-func (c *ColumnDCount) Encrypt() { c.Cipher.Text = encryptColumnDCount(c.Cipher.Text, c.Cipher.Key.Key, c.Cipher.Key.DKey) }
-func (c *ColumnDCount) Decrypt() { c.Cipher.Text = decryptColumnDCount(c.Cipher.Text, c.Cipher.Key.Key, c.Cipher.Key.DKey) }
-func (c *ColumnDCount) Verify() bool { return true }
+func (c *ColumnDCount) SetText(text []rune) { c.Cipher.Text = text }
+func (c *ColumnDCount) Encrypt() { c.Cipher.crypt(pureColumnDCount, true) }
+func (c *ColumnDCount) Decrypt() { c.Cipher.crypt(pureColumnDCount, false) }
+func (c *ColumnDCount) EncryptE() error { return c.Cipher.cryptE(verifyColumnDCount, nil, true, c.Encrypt) }
+func (c *ColumnDCount) DecryptE() error { return c.Cipher.cryptE(verifyColumnDCount, nil, false, c.Decrypt) }
+func (c *ColumnDCount) Verify() bool { return c.Cipher.verify(verifyColumnDCount) }
+
+func verifyColumnDCount(k *KeyColumnDCount) []error {
+	return collectErrors(
+		verifyNotEmpty("key", k.Key),
+		verifyNotEmpty("dkey", k.DKey),
+	)
+}
 
 func encryptColumnDCount(text, key, dkey []rune) []rune {
 	if len(dkey) < 2 {
@@ -254,6 +292,16 @@ func decryptColumnDCount(text, key, dkey []rune) []rune {
 
 func NewKeyColumnDLine(key []rune, fill bool) *KeyColumnDLine { return &KeyColumnDLine{Key: key, Fill: fill} }
 func NewColumnDLine(text []rune, key *KeyColumnDLine) *ColumnDLine { return &ColumnDLine{Cipher: &CipherClassical[KeyColumnDLine]{Text: text, Key: key}} }
+func EncryptColumnDLine(key *KeyColumnDLine, text []rune) ([]rune, error) { return cryptPure(verifyColumnDLine, nil, pureColumnDLine, key, text, true) }
+func DecryptColumnDLine(key *KeyColumnDLine, text []rune) ([]rune, error) { return cryptPure(verifyColumnDLine, nil, pureColumnDLine, key, text, false) }
+
+func pureColumnDLine(k *KeyColumnDLine, text []rune, encrypt bool, _ *Random) ([]rune, error) {
+	if encrypt {
+		return encryptColumnDLine(text, k.Key, k.Fill), nil
+	}
+
+	return decryptColumnDLine(text, k.Key, k.Fill), nil
+}
 
 type KeyColumnDLine struct {
 	Key []rune
@@ -263,9 +311,14 @@ type KeyColumnDLine struct {
 type ColumnDLine struct { Cipher *CipherClassical[KeyColumnDLine] }
 func (c *ColumnDLine) GetText() []rune { return c.Cipher.Text }
 func (c *ColumnDLine) GetErrors() []error { return c.Cipher.Errors }
-func (c *ColumnDLine) Encrypt() { c.Cipher.Text = encryptColumnDLine(c.Cipher.Text, c.Cipher.Key.Key, c.Cipher.Key.Fill) }
-func (c *ColumnDLine) Decrypt() { c.Cipher.Text = decryptColumnDLine(c.Cipher.Text, c.Cipher.Key.Key, c.Cipher.Key.Fill) }
-func (c *ColumnDLine) Verify() bool { return true }
+func (c *ColumnDLine) SetText(text []rune) { c.Cipher.Text = text }
+func (c *ColumnDLine) Encrypt() { c.Cipher.crypt(pureColumnDLine, true) }
+func (c *ColumnDLine) Decrypt() { c.Cipher.crypt(pureColumnDLine, false) }
+func (c *ColumnDLine) EncryptE() error { return c.Cipher.cryptE(verifyColumnDLine, nil, true, c.Encrypt) }
+func (c *ColumnDLine) DecryptE() error { return c.Cipher.cryptE(verifyColumnDLine, nil, false, c.Decrypt) }
+func (c *ColumnDLine) Verify() bool { return c.Cipher.verify(verifyColumnDLine) }
+
+func verifyColumnDLine(k *KeyColumnDLine) []error { return collectErrors(verifyNotEmpty("key", k.Key)) }
 
 func buildDLineGrid(text []rune, keyIndices []int, keySize int, fill bool) ([][]rune, int) {
 	block := 0
@@ -401,3 +454,60 @@ func decryptColumnDLine(text, key []rune, fill bool) []rune {
 
 	return result
 }
+
+func NewKeyNihilistTransposition(key []rune, byColumns bool) *KeyNihilistTransposition {
+	return &KeyNihilistTransposition{Key: key, ByColumns: byColumns}
+}
+func NewNihilistTransposition(text []rune, key *KeyNihilistTransposition) *NihilistTransposition {
+	return &NihilistTransposition{Cipher: &CipherClassical[KeyNihilistTransposition]{Text: text, Key: key}}
+}
+func EncryptNihilistTransposition(key *KeyNihilistTransposition, text []rune) ([]rune, error) { return cryptPure(verifyNihilistTransposition, nil, pureNihilistTransposition, key, text, true) }
+func DecryptNihilistTransposition(key *KeyNihilistTransposition, text []rune) ([]rune, error) { return cryptPure(verifyNihilistTransposition, nil, pureNihilistTransposition, key, text, false) }
+
+func pureNihilistTransposition(k *KeyNihilistTransposition, text []rune, encrypt bool, _ *Random) ([]rune, error) { return cryptNihilistTransposition(text, k.Key, k.ByColumns, encrypt), nil }
+
+type KeyNihilistTransposition struct {
+	Key []rune
+	ByColumns bool
+}
+
+type NihilistTransposition struct { Cipher *CipherClassical[KeyNihilistTransposition] }
+func (c *NihilistTransposition) GetText() []rune { return c.Cipher.Text }
+func (c *NihilistTransposition) GetErrors() []error { return c.Cipher.Errors }
+func (c *NihilistTransposition) SetText(text []rune) { c.Cipher.Text = text }
+func (c *NihilistTransposition) Encrypt() { c.Cipher.crypt(pureNihilistTransposition, true) }
+func (c *NihilistTransposition) Decrypt() { c.Cipher.crypt(pureNihilistTransposition, false) }
+func (c *NihilistTransposition) EncryptE() error { return c.Cipher.cryptE(verifyNihilistTransposition, nil, true, c.Encrypt) }
+func (c *NihilistTransposition) DecryptE() error { return c.Cipher.cryptE(verifyNihilistTransposition, nil, false, c.Decrypt) }
+func (c *NihilistTransposition) Verify() bool { return c.Cipher.verify(verifyNihilistTransposition) }
+
+func verifyNihilistTransposition(k *KeyNihilistTransposition) []error { return collectErrors(verifyNotEmpty("key", k.Key)) }
+
+// The text is written by rows in squares as wide as the key, the rows and columns of each square
+// are reordered by the key and then read by rows or columns. Cells past the end of the text stay empty.
+func cryptNihilistTransposition(text, key []rune, byColumns, encrypt bool) []rune {
+	result := make([]rune, len(text))
+	keyIndices := getSortedKeyIndices(key)
+	n := len(key)
+	sIndex := 0
+
+	for start := 0; start < len(text); start += n * n {
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				row, col := keyIndices[i], keyIndices[j]
+				if byColumns {
+					row, col = col, row
+				}
+
+				index := start + row * n + col
+				if index < len(text) {
+					i1, i2 := utils.SwapIf(index, sIndex, encrypt)
+					result[i1] = text[i2]
+					sIndex++
+				}
+			}
+		}
+	}
+
+	return result
+}
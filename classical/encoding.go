@@ -0,0 +1,446 @@
+package classical
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+type KeyDocument struct {
+	Cipher string `json:"cipher"`
+	Key json.RawMessage `json:"key"`
+}
+
+func MarshalKey(name string, key any) ([]byte, error) {
+	fields, err := encodeKey(name, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(KeyDocument{Cipher: name, Key: joinKeyFields(fields)})
+}
+
+func UnmarshalKey(data []byte) (string, any, error) {
+	var doc KeyDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return "", nil, err
+	}
+
+	key, err := decodeKey(doc.Cipher, doc.Key)
+	return doc.Cipher, key, err
+}
+
+func UnmarshalCipher(data []byte, text []rune) (ICipherClassical, error) {
+	name, key, err := UnmarshalKey(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return registry[name].NewCipher(text, key)
+}
+
+func FormatKey(name string, key any) (string, error) {
+	fields, err := encodeKey(name, key)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	sb.WriteString(name)
+	sb.WriteByte(':')
+	for i, f := range fields {
+		if i > 0 {
+			sb.WriteByte(';')
+		}
+		sb.WriteString(f.name)
+		sb.WriteByte('=')
+		sb.Write(f.value)
+	}
+
+	return sb.String(), nil
+}
+
+func ParseKey(s string) (string, any, error) {
+	name, rest, found := strings.Cut(s, ":")
+	if !found {
+		return "", nil, fmt.Errorf("%w: missing cipher name in %q", ErrInvalidKey, s)
+	}
+
+	fields := make([]keyField, 0)
+	for rest != "" {
+		fname, value, found := strings.Cut(rest, "=")
+		if !found {
+			return name, nil, fmt.Errorf("%w: missing value for %q", ErrInvalidKey, rest)
+		}
+
+		var raw json.RawMessage
+		dec := json.NewDecoder(strings.NewReader(value))
+		if err := dec.Decode(&raw); err != nil {
+			return name, nil, fmt.Errorf("%w: value of %s: %s", ErrInvalidKey, fname, err)
+		}
+
+		fields = append(fields, keyField{fname, raw})
+		rest = value[dec.InputOffset():]
+		if rest != "" {
+			if rest[0] != ';' || len(rest) == 1 {
+				return name, nil, fmt.Errorf("%w: expected another field after %s", ErrInvalidKey, fname)
+			}
+			rest = rest[1:]
+		}
+	}
+
+	key, err := decodeKey(name, joinKeyFields(fields))
+	return name, key, err
+}
+
+func ParseCipher(s string, text []rune) (ICipherClassical, error) {
+	name, key, err := ParseKey(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return registry[name].NewCipher(text, key)
+}
+
+type keyField struct {
+	name string
+	value json.RawMessage
+}
+
+func joinKeyFields(fields []keyField) json.RawMessage {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, f := range fields {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(f.name)
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(f.value)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes()
+}
+
+func encodeKey(name string, key any) ([]keyField, error) {
+	info, found := Lookup(name)
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownCipher, name)
+	}
+
+	proto, err := info.NewKey(Params{})
+	if err != nil {
+		return nil, err
+	}
+
+	if reflect.TypeOf(proto) != reflect.TypeOf(key) {
+		return nil, fmt.Errorf("%w: %s does not accept a key of type %T", ErrInvalidKey, name, key)
+	}
+
+	return marshalKeyFields(key)
+}
+
+func decodeKey(name string, data []byte) (any, error) {
+	info, found := Lookup(name)
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownCipher, name)
+	}
+
+	key, err := info.NewKey(Params{})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func keyFieldName(name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToLower(r)) + name[size:]
+}
+
+var runesType = reflect.TypeOf([]rune(nil))
+var denseType = reflect.TypeOf((*mat.Dense)(nil))
+var routeKeyType = reflect.TypeOf(route{})
+
+func marshalKey(key any) ([]byte, error) {
+	fields, err := marshalKeyFields(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return joinKeyFields(fields), nil
+}
+
+func marshalKeyFields(key any) ([]keyField, error) {
+	v := reflect.Indirect(reflect.ValueOf(key))
+	fields := make([]keyField, 0, v.NumField())
+
+	for i := 0; i < v.NumField(); i++ {
+		sf := v.Type().Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		value, err := marshalKeyValue(v.Field(i))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", sf.Name, err)
+		}
+
+		fields = append(fields, keyField{keyFieldName(sf.Name), value})
+	}
+
+	return fields, nil
+}
+
+func marshalKeyValue(v reflect.Value) ([]byte, error) {
+	switch v.Type() {
+	case runesType:
+		return json.Marshal(string(v.Interface().([]rune)))
+	case denseType:
+		m := v.Interface().(*mat.Dense)
+		if m == nil {
+			return []byte("null"), nil
+		}
+
+		r, _ := m.Dims()
+		rows := make([][]float64, r)
+		for i := range rows {
+			rows[i] = mat.Row(nil, i, m)
+		}
+		return json.Marshal(rows)
+	case routeKeyType:
+		for name, rt := range routeNames {
+			if rt == v.Interface().(route) {
+				return json.Marshal(name)
+			}
+		}
+		return nil, fmt.Errorf("unknown route %v", v.Interface())
+	}
+
+	switch v.Kind() {
+	case reflect.Int32:
+		if v.Int() == 0 {
+			return json.Marshal("")
+		}
+		return json.Marshal(string(rune(v.Int())))
+	case reflect.Int, reflect.Bool, reflect.String:
+		return json.Marshal(v.Interface())
+	case reflect.Slice:
+		if v.IsNil() {
+			return []byte("[]"), nil
+		}
+
+		var buf bytes.Buffer
+		buf.WriteByte('[')
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			value, err := marshalKeyValue(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			buf.Write(value)
+		}
+		buf.WriteByte(']')
+		return buf.Bytes(), nil
+	case reflect.Struct:
+		return marshalKey(v.Interface())
+	}
+
+	return nil, fmt.Errorf("unsupported key field type %s", v.Type())
+}
+
+// Every exported field must be given, a key is never completed from the one it is decoded into.
+func unmarshalKey(data []byte, key any) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	v := reflect.ValueOf(key).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		sf := v.Type().Field(i)
+		name := keyFieldName(sf.Name)
+		value, found := raw[name]
+		if !sf.IsExported() {
+			continue
+		} else if !found {
+			return fmt.Errorf("%w: missing field %s", ErrInvalidKey, name)
+		}
+
+		if err := unmarshalKeyValue(value, v.Field(i)); err != nil {
+			return fmt.Errorf("%w: %s: %s", ErrInvalidKey, name, err)
+		}
+		delete(raw, name)
+	}
+
+	for name := range raw {
+		return fmt.Errorf("%w: unknown field %s", ErrInvalidKey, name)
+	}
+
+	return nil
+}
+
+func unmarshalKeyValue(data []byte, v reflect.Value) error {
+	switch v.Type() {
+	case runesType:
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf([]rune(s)))
+		return nil
+	case denseType:
+		var rows [][]float64
+		if err := json.Unmarshal(data, &rows); err != nil {
+			return err
+		}
+		if rows == nil {
+			v.Set(reflect.Zero(denseType))
+			return nil
+		}
+		m := denseFromRows(rows)
+		if m == nil {
+			return fmt.Errorf("matrix rows are empty or uneven")
+		}
+		v.Set(reflect.ValueOf(m))
+		return nil
+	case routeKeyType:
+		var name string
+		if err := json.Unmarshal(data, &name); err != nil {
+			return err
+		}
+		rt, found := routeNames[name]
+		if !found {
+			return fmt.Errorf("unknown route %q", name)
+		}
+		v.Set(reflect.ValueOf(rt))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Int32:
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		rs := []rune(s)
+		if len(rs) > 1 {
+			return fmt.Errorf("%q is not a single rune", s)
+		}
+		if len(rs) == 0 {
+			v.SetInt(0)
+		} else {
+			v.SetInt(int64(rs[0]))
+		}
+		return nil
+	case reflect.Int, reflect.Bool, reflect.String:
+		return json.Unmarshal(data, v.Addr().Interface())
+	case reflect.Slice:
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return err
+		}
+		s := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := unmarshalKeyValue(item, s.Index(i)); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+		return nil
+	case reflect.Struct:
+		return unmarshalKey(data, v.Addr().Interface())
+	}
+
+	return fmt.Errorf("unsupported key field type %s", v.Type())
+}
+
+func (k KeyNone) MarshalJSON() ([]byte, error) { return marshalKey(&k) }
+func (k *KeyNone) UnmarshalJSON(data []byte) error { return unmarshalKey(data, k) }
+func (k KeyADFGVX) MarshalJSON() ([]byte, error) { return marshalKey(&k) }
+func (k *KeyADFGVX) UnmarshalJSON(data []byte) error { return unmarshalKey(data, k) }
+func (k KeyADFGX) MarshalJSON() ([]byte, error) { return marshalKey(&k) }
+func (k *KeyADFGX) UnmarshalJSON(data []byte) error { return unmarshalKey(data, k) }
+func (k KeyColumn) MarshalJSON() ([]byte, error) { return marshalKey(&k) }
+func (k *KeyColumn) UnmarshalJSON(data []byte) error { return unmarshalKey(data, k) }
+func (k KeyPolybius) MarshalJSON() ([]byte, error) { return marshalKey(&k) }
+func (k *KeyPolybius) UnmarshalJSON(data []byte) error { return unmarshalKey(data, k) }
+func (k KeyRoute) MarshalJSON() ([]byte, error) { return marshalKey(&k) }
+func (k *KeyRoute) UnmarshalJSON(data []byte) error { return unmarshalKey(data, k) }
+func (k KeyShift) MarshalJSON() ([]byte, error) { return marshalKey(&k) }
+func (k *KeyShift) UnmarshalJSON(data []byte) error { return unmarshalKey(data, k) }
+func (k KeyZigzag) MarshalJSON() ([]byte, error) { return marshalKey(&k) }
+func (k *KeyZigzag) UnmarshalJSON(data []byte) error { return unmarshalKey(data, k) }
+func (k KeyScytale) MarshalJSON() ([]byte, error) { return marshalKey(&k) }
+func (k *KeyScytale) UnmarshalJSON(data []byte) error { return unmarshalKey(data, k) }
+func (k KeyMyszkowski) MarshalJSON() ([]byte, error) { return marshalKey(&k) }
+func (k *KeyMyszkowski) UnmarshalJSON(data []byte) error { return unmarshalKey(data, k) }
+func (k KeyCaesar) MarshalJSON() ([]byte, error) { return marshalKey(&k) }
+func (k *KeyCaesar) UnmarshalJSON(data []byte) error { return unmarshalKey(data, k) }
+func (k KeyColumnDCount) MarshalJSON() ([]byte, error) { return marshalKey(&k) }
+func (k *KeyColumnDCount) UnmarshalJSON(data []byte) error { return unmarshalKey(data, k) }
+func (k KeyColumnDLine) MarshalJSON() ([]byte, error) { return marshalKey(&k) }
+func (k *KeyColumnDLine) UnmarshalJSON(data []byte) error { return unmarshalKey(data, k) }
+func (k KeyVigenere) MarshalJSON() ([]byte, error) { return marshalKey(&k) }
+func (k *KeyVigenere) UnmarshalJSON(data []byte) error { return unmarshalKey(data, k) }
+func (k KeySubstitute) MarshalJSON() ([]byte, error) { return marshalKey(&k) }
+func (k *KeySubstitute) UnmarshalJSON(data []byte) error { return unmarshalKey(data, k) }
+func (k KeyAutokey) MarshalJSON() ([]byte, error) { return marshalKey(&k) }
+func (k *KeyAutokey) UnmarshalJSON(data []byte) error { return unmarshalKey(data, k) }
+func (k KeyPlayfair) MarshalJSON() ([]byte, error) { return marshalKey(&k) }
+func (k *KeyPlayfair) UnmarshalJSON(data []byte) error { return unmarshalKey(data, k) }
+func (k KeyAffine) MarshalJSON() ([]byte, error) { return marshalKey(&k) }
+func (k *KeyAffine) UnmarshalJSON(data []byte) error { return unmarshalKey(data, k) }
+func (k KeyAtbash) MarshalJSON() ([]byte, error) { return marshalKey(&k) }
+func (k *KeyAtbash) UnmarshalJSON(data []byte) error { return unmarshalKey(data, k) }
+func (k KeyBeaufort) MarshalJSON() ([]byte, error) { return marshalKey(&k) }
+func (k *KeyBeaufort) UnmarshalJSON(data []byte) error { return unmarshalKey(data, k) }
+func (k KeyShiftAlphabet) MarshalJSON() ([]byte, error) { return marshalKey(&k) }
+func (k *KeyShiftAlphabet) UnmarshalJSON(data []byte) error { return unmarshalKey(data, k) }
+func (k KeyChaocipher) MarshalJSON() ([]byte, error) { return marshalKey(&k) }
+func (k *KeyChaocipher) UnmarshalJSON(data []byte) error { return unmarshalKey(data, k) }
+func (k KeyHill) MarshalJSON() ([]byte, error) { return marshalKey(&k) }
+func (k *KeyHill) UnmarshalJSON(data []byte) error { return unmarshalKey(data, k) }
+func (k KeyTwoSquareV) MarshalJSON() ([]byte, error) { return marshalKey(&k) }
+func (k *KeyTwoSquareV) UnmarshalJSON(data []byte) error { return unmarshalKey(data, k) }
+func (k KeyTwoSquareH) MarshalJSON() ([]byte, error) { return marshalKey(&k) }
+func (k *KeyTwoSquareH) UnmarshalJSON(data []byte) error { return unmarshalKey(data, k) }
+func (k KeyFourSquare) MarshalJSON() ([]byte, error) { return marshalKey(&k) }
+func (k *KeyFourSquare) UnmarshalJSON(data []byte) error { return unmarshalKey(data, k) }
+func (k KeyBifid) MarshalJSON() ([]byte, error) { return marshalKey(&k) }
+func (k *KeyBifid) UnmarshalJSON(data []byte) error { return unmarshalKey(data, k) }
+func (k KeyTrifid) MarshalJSON() ([]byte, error) { return marshalKey(&k) }
+func (k *KeyTrifid) UnmarshalJSON(data []byte) error { return unmarshalKey(data, k) }
+func (k KeyNihilist) MarshalJSON() ([]byte, error) { return marshalKey(&k) }
+func (k *KeyNihilist) UnmarshalJSON(data []byte) error { return unmarshalKey(data, k) }
+func (k KeyNihilistTransposition) MarshalJSON() ([]byte, error) { return marshalKey(&k) }
+func (k *KeyNihilistTransposition) UnmarshalJSON(data []byte) error { return unmarshalKey(data, k) }
+func (k KeyCheckerboard) MarshalJSON() ([]byte, error) { return marshalKey(&k) }
+func (k *KeyCheckerboard) UnmarshalJSON(data []byte) error { return unmarshalKey(data, k) }
+func (k KeyVIC) MarshalJSON() ([]byte, error) { return marshalKey(&k) }
+func (k *KeyVIC) UnmarshalJSON(data []byte) error { return unmarshalKey(data, k) }
+func (k KeyEnigma) MarshalJSON() ([]byte, error) { return marshalKey(&k) }
+func (k *KeyEnigma) UnmarshalJSON(data []byte) error { return unmarshalKey(data, k) }
+func (k KeyRotorMachine) MarshalJSON() ([]byte, error) { return marshalKey(&k) }
+func (k *KeyRotorMachine) UnmarshalJSON(data []byte) error { return unmarshalKey(data, k) }
+func (k KeySIGABA) MarshalJSON() ([]byte, error) { return marshalKey(&k) }
+func (k *KeySIGABA) UnmarshalJSON(data []byte) error { return unmarshalKey(data, k) }
+func (k KeyM209) MarshalJSON() ([]byte, error) { return marshalKey(&k) }
+func (k *KeyM209) UnmarshalJSON(data []byte) error { return unmarshalKey(data, k) }
+func (k KeySolitaire) MarshalJSON() ([]byte, error) { return marshalKey(&k) }
+func (k *KeySolitaire) UnmarshalJSON(data []byte) error { return unmarshalKey(data, k) }
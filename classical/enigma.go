@@ -0,0 +1,118 @@
+package classical
+
+import "sort"
+
+type enigmaRotor struct {
+	wiring string
+	notches string
+}
+
+var enigmaRotors = map[string]enigmaRotor{
+	"I": {"EKMFLGDQVZNTOWYHXUSPAIBRCJ", "Q"},
+	"II": {"AJDKSIRUXBLHWTMCQGZNPYFVOE", "E"},
+	"III": {"BDFHJLCPRTXVZNYEIWGAKMUSQO", "V"},
+	"IV": {"ESOVPZJAYQUIRHXLNFTGKDCMWB", "J"},
+	"V": {"VZBRGITYUPSDNHLXAWMJQOFECK", "Z"},
+	"VI": {"JPGVOUMFYQBENHZRDKASXLICTW", "ZM"},
+	"VII": {"NZJHGRCXMYSWBOUFAIVLPEKQDT", "ZM"},
+	"VIII": {"FKQHTLXOCBJSPDZRAMEWNIUYGV", "ZM"},
+	"Beta": {"LEYJVCNIXWPBQMDRTAKZGFUHOS", ""},
+	"Gamma": {"FSOKANUERHMBTIYCWLQPZXVGJD", ""},
+}
+
+var enigmaReflectors = map[string]string{
+	"A": "EJMZALYXVBWFCRQUONTSPIKHGD",
+	"B": "YRUHQSLDPXNGOKMIEBFZCWVJAT",
+	"C": "FVPJIAOYEDRZXWGCTKUQSBNMHL",
+	"B-thin": "ENKQAUYWJICOPBLMDXZVFTHRGS",
+	"C-thin": "RDOBJNTKVEHMLFCWZAXGYIPSUQ",
+}
+
+func EnigmaRotors() []string { return sortedNames(enigmaRotors) }
+func EnigmaReflectors() []string { return sortedNames(enigmaReflectors) }
+
+func sortedNames[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func NewKeyEnigma(reflector string, rotors []string, rings, positions, plugboard []rune) *KeyEnigma {
+	return &KeyEnigma{Reflector: reflector, Rotors: rotors, Rings: rings, Positions: positions, Plugboard: plugboard}
+}
+func NewEnigma(text []rune, key *KeyEnigma) *Enigma { return &Enigma{Cipher: &CipherClassical[KeyEnigma]{Text: text, Key: key}} }
+func EncryptEnigma(key *KeyEnigma, text []rune) ([]rune, error) { return cryptPure(verifyEnigma, checkEnigma, pureEnigma, key, text, true) }
+func DecryptEnigma(key *KeyEnigma, text []rune) ([]rune, error) { return cryptPure(verifyEnigma, checkEnigma, pureEnigma, key, text, false) }
+
+func pureEnigma(k *KeyEnigma, text []rune, encrypt bool, _ *Random) ([]rune, error) { return cryptEnigma(text, k), nil }
+
+// Rotors, rings and positions go from left to right. Three rotors make an M3, four make an M4
+// whose leftmost rotor is Beta or Gamma in front of a thin reflector. The plugboard is a list of
+// swapped pairs, spaces between pairs are ignored.
+type KeyEnigma struct {
+	Reflector string
+	Rotors []string
+	Rings []rune
+	Positions []rune
+	Plugboard []rune
+}
+
+type Enigma struct { Cipher *CipherClassical[KeyEnigma] }
+func (c *Enigma) GetText() []rune { return c.Cipher.Text }
+func (c *Enigma) GetErrors() []error { return c.Cipher.Errors }
+func (c *Enigma) SetText(text []rune) { c.Cipher.Text = text }
+func (c *Enigma) Encrypt() { c.Cipher.apply([]rune(AlphabetL), true, c.Cipher.pure(pureEnigma, true)) }
+func (c *Enigma) Decrypt() { c.Encrypt() }
+func (c *Enigma) EncryptE() error { return c.Cipher.cryptE(verifyEnigma, checkEnigma, true, c.Encrypt) }
+func (c *Enigma) DecryptE() error { return c.Cipher.cryptE(verifyEnigma, checkEnigma, false, c.Decrypt) }
+func (c *Enigma) Verify() bool { return c.Cipher.verify(verifyEnigma) }
+
+func verifyEnigma(k *KeyEnigma) []error {
+	alphabet := []rune(AlphabetL)
+	errs := collectErrors(
+		verifyLength("rings", k.Rings, len(k.Rotors)),
+		verifyRunesIn("rings", k.Rings, alphabet),
+		verifyLength("positions", k.Positions, len(k.Rotors)),
+		verifyRunesIn("positions", k.Positions, alphabet),
+	)
+
+	if _, found := enigmaReflectors[k.Reflector]; !found {
+		errs = append(errs, keyError("unknown reflector %q", k.Reflector))
+	}
+
+	if len(k.Rotors) != 3 && len(k.Rotors) != 4 {
+		errs = append(errs, keyError("Enigma takes 3 or 4 rotors, got %d", len(k.Rotors)))
+	}
+
+	thin := k.Reflector == "B-thin" || k.Reflector == "C-thin"
+	if len(k.Rotors) == 4 && !thin || len(k.Rotors) == 3 && thin {
+		errs = append(errs, keyError("reflector %s does not fit %d rotors", k.Reflector, len(k.Rotors)))
+	}
+
+	errs = append(errs, verifyRotorNames(k.Rotors, enigmaRotors)...)
+	for i, name := range k.Rotors {
+		if greek := name == "Beta" || name == "Gamma"; greek != (len(k.Rotors) == 4 && i == 0) {
+			errs = append(errs, keyError("rotor %s cannot be at position %d", name, i + 1))
+		}
+	}
+
+	return append(errs, verifyPlugboard(k.Plugboard, alphabet)...)
+}
+
+func checkEnigma(k *KeyEnigma, text []rune, encrypt bool) error { return checkAlphabet(text, []rune(AlphabetL)) }
+
+func enigmaRotorMachine(k *KeyEnigma) *KeyRotorMachine {
+	rotors := make([]KeyRotor, len(k.Rotors))
+	for i, name := range k.Rotors {
+		rotor := enigmaRotors[name]
+		rotors[i] = KeyRotor{Wiring: []rune(rotor.wiring), Notches: []rune(rotor.notches), Ring: k.Rings[i], Position: k.Positions[i], Stator: len(k.Rotors) == 4 && i == 0}
+	}
+
+	return NewKeyRotorMachine([]rune(AlphabetL), plugboardEntry(k.Plugboard, []rune(AlphabetL)), rotors, []rune(enigmaReflectors[k.Reflector]), SteppingEnigma)
+}
+
+func cryptEnigma(text []rune, k *KeyEnigma) []rune { return cryptRotorMachine(text, enigmaRotorMachine(k), true) }
@@ -0,0 +1,43 @@
+package classical
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidKey = errors.New("invalid key")
+var ErrInvalidText = errors.New("invalid text")
+
+type InvalidRuneError struct {
+	Position int
+	Rune rune
+}
+
+func (e *InvalidRuneError) Error() string {
+	return fmt.Sprintf("%v: %q at position %d is not in the alphabet", ErrInvalidText, e.Rune, e.Position)
+}
+func (e *InvalidRuneError) Unwrap() error { return ErrInvalidText }
+
+type InvalidLengthError struct {
+	Length int
+	Multiple int
+}
+
+func (e *InvalidLengthError) Error() string {
+	return fmt.Sprintf("%v: length %d is not a multiple of %d", ErrInvalidText, e.Length, e.Multiple)
+}
+func (e *InvalidLengthError) Unwrap() error { return ErrInvalidText }
+
+func keyError(format string, a ...any) error {
+	return fmt.Errorf("%w: %s", ErrInvalidKey, fmt.Sprintf(format, a...))
+}
+
+type InvalidNumberError struct {
+	Position int
+	Number int
+}
+
+func (e *InvalidNumberError) Error() string {
+	return fmt.Sprintf("%v: number %d at position %d is not in the square", ErrInvalidText, e.Number, e.Position)
+}
+func (e *InvalidNumberError) Unwrap() error { return ErrInvalidText }
@@ -0,0 +1,533 @@
+package classical
+
+import (
+	"cryptochev/utils"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func RandomKeySubstitute(alphabet []rune) (*KeySubstitute, error) { return defaultRandom.KeySubstitute(alphabet) }
+func RandomKeyShift() *KeyShift { return defaultRandom.KeyShift() }
+func RandomKeyShiftAlphabet(alphabet []rune) (*KeyShiftAlphabet, error) { return defaultRandom.KeyShiftAlphabet(alphabet) }
+func RandomKeyCaesar() *KeyCaesar { return defaultRandom.KeyCaesar() }
+func RandomKeyAffine(alphabet []rune) (*KeyAffine, error) { return defaultRandom.KeyAffine(alphabet) }
+func RandomKeyAtbash(alphabet []rune) (*KeyAtbash, error) { return defaultRandom.KeyAtbash(alphabet) }
+func RandomKeyChaocipher(alphabet []rune) (*KeyChaocipher, error) { return defaultRandom.KeyChaocipher(alphabet) }
+func RandomKeyVigenere(alphabet []rune, length int) (*KeyVigenere, error) { return defaultRandom.KeyVigenere(alphabet, length) }
+func RandomKeyGronsfeld(alphabet []rune, length int) (*KeyVigenere, error) { return defaultRandom.KeyGronsfeld(alphabet, length) }
+func RandomKeyAutokey(alphabet []rune, length int) (*KeyAutokey, error) { return defaultRandom.KeyAutokey(alphabet, length) }
+func RandomKeyBeaufort(alphabet []rune, length int) (*KeyBeaufort, error) { return defaultRandom.KeyBeaufort(alphabet, length) }
+func RandomKeyPolybius(alphabet, header []rune) (*KeyPolybius, error) { return defaultRandom.KeyPolybius(alphabet, header) }
+func RandomKeyADFGX(width int) (*KeyADFGX, error) { return defaultRandom.KeyADFGX(width) }
+func RandomKeyADFGVX(width int) (*KeyADFGVX, error) { return defaultRandom.KeyADFGVX(width) }
+func RandomKeyBifid(alphabet []rune, maxPeriod int) (*KeyBifid, error) { return defaultRandom.KeyBifid(alphabet, maxPeriod) }
+func RandomKeyTrifid(filler rune, maxPeriod int) (*KeyTrifid, error) { return defaultRandom.KeyTrifid(filler, maxPeriod) }
+func RandomKeyPlayfair(alphabet []rune) (*KeyPlayfair, error) { return defaultRandom.KeyPlayfair(alphabet) }
+func RandomKeyTwoSquareV(alphabet []rune) (*KeyTwoSquareV, error) { return defaultRandom.KeyTwoSquareV(alphabet) }
+func RandomKeyTwoSquareH(alphabet []rune) (*KeyTwoSquareH, error) { return defaultRandom.KeyTwoSquareH(alphabet) }
+func RandomKeyFourSquare(alphabet []rune) (*KeyFourSquare, error) { return defaultRandom.KeyFourSquare(alphabet) }
+func RandomKeyHill(alphabet []rune, size int) (*KeyHill, error) { return defaultRandom.KeyHill(alphabet, size) }
+func RandomKeyColumn(alphabet []rune, width int) (*KeyColumn, error) { return defaultRandom.KeyColumn(alphabet, width) }
+func RandomKeyMyszkowski(alphabet []rune, width int) (*KeyMyszkowski, error) { return defaultRandom.KeyMyszkowski(alphabet, width) }
+func RandomKeyColumnDCount(alphabet []rune, width, dwidth int) (*KeyColumnDCount, error) { return defaultRandom.KeyColumnDCount(alphabet, width, dwidth) }
+func RandomKeyColumnDLine(alphabet []rune, width int) (*KeyColumnDLine, error) { return defaultRandom.KeyColumnDLine(alphabet, width) }
+func RandomKeyNihilist(alphabet, header []rune, length int) (*KeyNihilist, error) { return defaultRandom.KeyNihilist(alphabet, header, length) }
+func RandomKeyNihilistTransposition(alphabet []rune, width int) (*KeyNihilistTransposition, error) { return defaultRandom.KeyNihilistTransposition(alphabet, width) }
+func RandomKeyCheckerboard(runes []rune, blanks int, escape rune) (*KeyCheckerboard, error) { return defaultRandom.KeyCheckerboard(runes, blanks, escape) }
+func RandomKeyVIC(runes []rune, escape rune) (*KeyVIC, error) { return defaultRandom.KeyVIC(runes, escape) }
+func RandomKeyEnigma(rotors, plugs int) (*KeyEnigma, error) { return defaultRandom.KeyEnigma(rotors, plugs) }
+func RandomKeyRotorMachine(alphabet []rune, rotors int, reflector bool) (*KeyRotorMachine, error) { return defaultRandom.KeyRotorMachine(alphabet, rotors, reflector) }
+func RandomKeySIGABA() *KeySIGABA { return defaultRandom.KeySIGABA() }
+func RandomKeyM209() *KeyM209 { return defaultRandom.KeyM209() }
+func RandomKeySolitaire() *KeySolitaire { return defaultRandom.KeySolitaire() }
+func RandomKeyZigzag(maxLines int) (*KeyZigzag, error) { return defaultRandom.KeyZigzag(maxLines) }
+func RandomKeyScytale(maxLines int) (*KeyScytale, error) { return defaultRandom.KeyScytale(maxLines) }
+func RandomKeyRoute(maxWidth int) (*KeyRoute, error) { return defaultRandom.KeyRoute(maxWidth) }
+
+func (r *Random) between(min, max int) int { return min + r.Intn(max - min + 1) }
+
+// The generators check their sizes before drawing, so that a fuzzer with bad sizes gets an error.
+func randomAlphabet(alphabet []rune) error {
+	if len(alphabet) < 2 {
+		return keyError("random key needs an alphabet of at least 2 runes, got %d", len(alphabet))
+	}
+
+	return nil
+}
+
+func randomSize(name string, size, min int) error {
+	if size < min {
+		return keyError("random key needs a %s of at least %d, got %d", name, min, size)
+	}
+
+	return nil
+}
+
+func (r *Random) Word(alphabet []rune, length int) ([]rune, error) {
+	if len(alphabet) == 0 || length < 0 {
+		return nil, keyError("word of %d runes cannot be drawn from %d runes", length, len(alphabet))
+	}
+
+	word := make([]rune, length)
+	for i := range word {
+		word[i] = r.RuneFrom(alphabet)
+	}
+
+	return word, nil
+}
+
+// Keyword returns length distinct runes of the alphabet, so that sorting it has no ties.
+func (r *Random) Keyword(alphabet []rune, length int) ([]rune, error) {
+	if length < 0 || length > len(alphabet) {
+		return nil, keyError("keyword of %d runes cannot be drawn without repeats from %d runes", length, len(alphabet))
+	}
+
+	return r.Shuffle(append([]rune{}, alphabet...))[:length], nil
+}
+
+func (r *Random) names(names []string) []string {
+	for i := len(names) - 1; i > 0; i-- {
+		j := r.Intn(i + 1)
+		names[i], names[j] = names[j], names[i]
+	}
+
+	return names
+}
+
+func (r *Random) KeySubstitute(alphabet []rune) (*KeySubstitute, error) {
+	if err := randomAlphabet(alphabet); err != nil {
+		return nil, err
+	}
+
+	return NewKeySubstitute(alphabet, r.Shuffle(append([]rune{}, alphabet...))), nil
+}
+
+func (r *Random) KeyShift() *KeyShift { return NewKeyShift(r.between(1, 25)) }
+
+func (r *Random) KeyShiftAlphabet(alphabet []rune) (*KeyShiftAlphabet, error) {
+	if err := randomAlphabet(alphabet); err != nil {
+		return nil, err
+	}
+
+	return NewKeyShiftAlphabet(alphabet, r.between(1, len(alphabet) - 1)), nil
+}
+
+func (r *Random) KeyCaesar() *KeyCaesar { return NewKeyCaesar(r.between(1, 25)) }
+
+func (r *Random) KeyAffine(alphabet []rune) (*KeyAffine, error) {
+	if err := randomAlphabet(alphabet); err != nil {
+		return nil, err
+	}
+
+	coprimes := utils.Coprimes(len(alphabet))
+	return NewKeyAffine(alphabet, coprimes[r.Intn(len(coprimes))], r.Intn(len(alphabet))), nil
+}
+
+func (r *Random) KeyAtbash(alphabet []rune) (*KeyAtbash, error) {
+	if err := randomAlphabet(alphabet); err != nil {
+		return nil, err
+	}
+
+	return NewKeyAtbash(r.Shuffle(append([]rune{}, alphabet...))), nil
+}
+
+func (r *Random) KeyChaocipher(alphabet []rune) (*KeyChaocipher, error) {
+	if err := randomAlphabet(alphabet); err != nil {
+		return nil, err
+	}
+
+	return NewKeyChaocipher(r.Shuffle(append([]rune{}, alphabet...)), r.Shuffle(append([]rune{}, alphabet...))), nil
+}
+
+// Draws a periodic key of length runes of the alphabet.
+func (r *Random) periodic(alphabet []rune, length int) ([]rune, error) {
+	if err := randomAlphabet(alphabet); err != nil {
+		return nil, err
+	} else if err := randomSize("key length", length, 1); err != nil {
+		return nil, err
+	}
+
+	return r.Word(alphabet, length)
+}
+
+func (r *Random) KeyVigenere(alphabet []rune, length int) (*KeyVigenere, error) {
+	key, err := r.periodic(alphabet, length)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKeyVigenere(alphabet, key), nil
+}
+
+func (r *Random) KeyGronsfeld(alphabet []rune, length int) (*KeyVigenere, error) {
+	digits := []rune("0123456789")
+	if len(alphabet) < len(digits) {
+		digits = digits[:len(alphabet)]
+	}
+
+	key, err := r.periodic(digits, length)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKeyVigenere(alphabet, key), nil
+}
+
+func (r *Random) KeyAutokey(alphabet []rune, length int) (*KeyAutokey, error) {
+	primer, err := r.periodic(alphabet, length)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKeyAutokey(alphabet, primer), nil
+}
+
+func (r *Random) KeyBeaufort(alphabet []rune, length int) (*KeyBeaufort, error) {
+	key, err := r.periodic(alphabet, length)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKeyBeaufort(alphabet, key), nil
+}
+
+func (r *Random) KeyPolybius(alphabet, header []rune) (*KeyPolybius, error) {
+	if err := randomAlphabet(alphabet); err != nil {
+		return nil, err
+	}
+
+	return NewKeyPolybius(r.Shuffle(append([]rune{}, alphabet...)), header), nil
+}
+
+func (r *Random) KeyADFGX(width int) (*KeyADFGX, error) {
+	keyword, err := r.Keyword([]rune(AlphabetL), width)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKeyADFGX(r.AlphabetL25(), keyword), nil
+}
+
+func (r *Random) KeyADFGVX(width int) (*KeyADFGVX, error) {
+	keyword, err := r.Keyword([]rune(AlphabetL), width)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKeyADFGVX(r.AlphabetL36(), keyword), nil
+}
+
+func (r *Random) KeyBifid(alphabet []rune, maxPeriod int) (*KeyBifid, error) {
+	if err := randomAlphabet(alphabet); err != nil {
+		return nil, err
+	} else if err := randomSize("maximum period", maxPeriod, 2); err != nil {
+		return nil, err
+	}
+
+	return NewKeyBifid(r.Shuffle(append([]rune{}, alphabet...)), r.between(2, maxPeriod)), nil
+}
+
+func (r *Random) KeyTrifid(filler rune, maxPeriod int) (*KeyTrifid, error) {
+	if err := randomSize("maximum period", maxPeriod, 2); err != nil {
+		return nil, err
+	}
+
+	return NewKeyTrifid(r.AlphabetKey(append([]rune(AlphabetL), filler), nil), r.between(2, maxPeriod)), nil
+}
+
+func (r *Random) KeyPlayfair(alphabet []rune) (*KeyPlayfair, error) {
+	if err := randomAlphabet(alphabet); err != nil {
+		return nil, err
+	}
+
+	square := r.Shuffle(append([]rune{}, alphabet...))
+	return NewKeyPlayfair(square, r.RuneFrom(square)), nil
+}
+
+func (r *Random) KeyTwoSquareV(alphabet []rune) (*KeyTwoSquareV, error) {
+	if err := randomAlphabet(alphabet); err != nil {
+		return nil, err
+	}
+
+	return NewKeyTwoSquareV(r.Shuffle(append([]rune{}, alphabet...)), r.Shuffle(append([]rune{}, alphabet...)), false), nil
+}
+
+func (r *Random) KeyTwoSquareH(alphabet []rune) (*KeyTwoSquareH, error) {
+	if err := randomAlphabet(alphabet); err != nil {
+		return nil, err
+	}
+
+	return NewKeyTwoSquareH(r.Shuffle(append([]rune{}, alphabet...)), r.Shuffle(append([]rune{}, alphabet...)), false), nil
+}
+
+func (r *Random) KeyFourSquare(alphabet []rune) (*KeyFourSquare, error) {
+	if err := randomAlphabet(alphabet); err != nil {
+		return nil, err
+	}
+
+	return NewKeyFourSquare(alphabet, r.Shuffle(append([]rune{}, alphabet...)), r.Shuffle(append([]rune{}, alphabet...)), alphabet), nil
+}
+
+// KeyHill draws matrices until one is invertible modulo the alphabet length, there is always one
+// such as the identity once the alphabet has 2 runes.
+func (r *Random) KeyHill(alphabet []rune, size int) (*KeyHill, error) {
+	if err := randomAlphabet(alphabet); err != nil {
+		return nil, err
+	} else if err := randomSize("matrix size", size, 1); err != nil {
+		return nil, err
+	}
+
+	values := make([]float64, size * size)
+	for {
+		for i := range values {
+			values[i] = float64(r.Intn(len(alphabet)))
+		}
+
+		key := NewKeyHill(alphabet, mat.NewDense(size, size, append([]float64{}, values...)))
+		if len(verifyHill(key)) == 0 {
+			return key, nil
+		}
+	}
+}
+
+func (r *Random) KeyColumn(alphabet []rune, width int) (*KeyColumn, error) {
+	keyword, err := r.Keyword(alphabet, width)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKeyColumn(keyword), nil
+}
+
+// KeyMyszkowski draws at least two distinct runes, and fewer than the width when it is above 2 so
+// that at least one of them repeats. A key of a single rune would leave the text as it is.
+func (r *Random) KeyMyszkowski(alphabet []rune, width int) (*KeyMyszkowski, error) {
+	if width < 2 || len(alphabet) < 2 {
+		return nil, keyError("Myszkowski key needs a width and an alphabet of at least 2, got %d and %d", width, len(alphabet))
+	}
+
+	max := width - 1
+	if max > len(alphabet) {
+		max = len(alphabet)
+	}
+
+	n := 2
+	if max > 2 {
+		n = r.between(2, max)
+	}
+
+	distinct, _ := r.Keyword(alphabet, n)
+	repeats, _ := r.Word(distinct, width - len(distinct))
+	key := append(append([]rune{}, distinct...), repeats...)
+
+	return NewKeyMyszkowski(r.Shuffle(key)), nil
+}
+
+func (r *Random) KeyColumnDCount(alphabet []rune, width, dwidth int) (*KeyColumnDCount, error) {
+	keyword, err := r.Keyword(alphabet, width)
+	if err != nil {
+		return nil, err
+	}
+
+	dkeyword, err := r.Keyword(alphabet, dwidth)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKeyColumnDCount(keyword, dkeyword), nil
+}
+
+func (r *Random) KeyColumnDLine(alphabet []rune, width int) (*KeyColumnDLine, error) {
+	keyword, err := r.Keyword(alphabet, width)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKeyColumnDLine(keyword, r.Intn(2) == 1), nil
+}
+
+func (r *Random) KeyNihilist(alphabet, header []rune, length int) (*KeyNihilist, error) {
+	square := r.Shuffle(append([]rune{}, alphabet...))
+	key, err := r.periodic(square, length)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKeyNihilist(square, header, key), nil
+}
+
+func (r *Random) KeyNihilistTransposition(alphabet []rune, width int) (*KeyNihilistTransposition, error) {
+	keyword, err := r.Keyword(alphabet, width)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKeyNihilistTransposition(keyword, r.Intn(2) == 1), nil
+}
+
+// Checkerboard lays the shuffled runes out on 10 columns with blanks spread over the first row,
+// so it takes 9 * blanks + 10 runes.
+func (r *Random) Checkerboard(runes []rune, blanks int) ([]rune, error) {
+	if blanks < 0 || blanks > 10 || len(runes) != 9 * blanks + 10 {
+		return nil, keyError("checkerboard with %d blanks needs %d runes, got %d", blanks, 9 * blanks + 10, len(runes))
+	}
+
+	shuffled := r.Shuffle(append([]rune{}, runes...))
+	result := make([]rune, 10, len(runes) + blanks)
+	for _, col := range r.Shuffle([]rune("0123456789"))[:blanks] {
+		result[col - '0'] = ' '
+	}
+
+	for i := range result {
+		if result[i] == 0 {
+			result[i], shuffled = shuffled[0], shuffled[1:]
+		}
+	}
+
+	return append(result, shuffled...), nil
+}
+
+func (r *Random) KeyCheckerboard(runes []rune, blanks int, escape rune) (*KeyCheckerboard, error) {
+	checkerboard, err := r.Checkerboard(runes, blanks)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKeyCheckerboard(checkerboard, r.Shuffle([]rune("0123456789")), escape), nil
+}
+
+func (r *Random) KeyVIC(runes []rune, escape rune) (*KeyVIC, error) {
+	checkerboard, err := r.Checkerboard(runes, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	digits := []rune("0123456789")
+	phrase, _ := r.Word([]rune(AlphabetL), 20)
+	date, _ := r.Word(digits, 6)
+	indicator, _ := r.Word(digits, 5)
+
+	return NewKeyVIC(phrase, date, r.between(1, 16), indicator, checkerboard, escape), nil
+}
+
+// KeyEnigma draws an M3 key for 3 rotors and an M4 key for 4 rotors, with plugs pairs on the plugboard.
+func (r *Random) KeyEnigma(rotors, plugs int) (*KeyEnigma, error) {
+	alphabet := []rune(AlphabetL)
+	names := r.names([]string{"I", "II", "III", "IV", "V", "VI", "VII", "VIII"})[:3]
+
+	reflector := [...]string{"B", "C"}[r.Intn(2)]
+	if rotors == 4 {
+		names = append([]string{[...]string{"Beta", "Gamma"}[r.Intn(2)]}, names...)
+		reflector += "-thin"
+	}
+
+	plugboard, err := r.Keyword(alphabet, plugs * 2)
+	if err != nil {
+		return nil, err
+	}
+
+	rings, _ := r.Word(alphabet, len(names))
+	positions, _ := r.Word(alphabet, len(names))
+
+	return NewKeyEnigma(reflector, names, rings, positions, plugboard), nil
+}
+
+// KeyRotorMachine draws odometer rotors with one notch each and a reflector pairing the alphabet,
+// which then needs an even length.
+func (r *Random) KeyRotorMachine(alphabet []rune, rotors int, reflector bool) (*KeyRotorMachine, error) {
+	if err := randomAlphabet(alphabet); err != nil {
+		return nil, err
+	} else if err := randomSize("number of rotors", rotors, 1); err != nil {
+		return nil, err
+	} else if reflector && len(alphabet) % 2 != 0 {
+		return nil, keyError("reflector cannot pair the %d runes of an odd alphabet", len(alphabet))
+	}
+
+	keys := make([]KeyRotor, rotors)
+	for i := range keys {
+		keys[i] = KeyRotor{Wiring: r.Shuffle(append([]rune{}, alphabet...)), Notches: []rune{r.RuneFrom(alphabet)}, Ring: r.RuneFrom(alphabet), Position: r.RuneFrom(alphabet)}
+	}
+
+	var reflection []rune
+	if reflector {
+		amap := buildIndexMap(alphabet)
+		pairs := r.Shuffle(append([]rune{}, alphabet...))
+		reflection = make([]rune, len(alphabet))
+		for i := 0; i + 1 < len(pairs); i += 2 {
+			reflection[amap[pairs[i]]], reflection[amap[pairs[i + 1]]] = pairs[i + 1], pairs[i]
+		}
+	}
+
+	return NewKeyRotorMachine(alphabet, r.Shuffle(append([]rune{}, alphabet...)), keys, reflection, SteppingOdometer), nil
+}
+
+func (r *Random) KeySIGABA() *KeySIGABA {
+	names := r.names([]string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9"})
+	for i := range names {
+		if r.Intn(2) == 1 {
+			names[i] += "R"
+		}
+	}
+
+	cipher, _ := r.Word([]rune(AlphabetL), 5)
+	control, _ := r.Word([]rune(AlphabetL), 5)
+	index, _ := r.Word([]rune("0123456789"), 5)
+
+	return NewKeySIGABA(names[:5], names[5:], r.names([]string{"0", "1", "2", "3", "4"}), cipher, control, index)
+}
+
+// KeyM209 activates about half of the pins and puts one or two lugs on each bar.
+func (r *Random) KeyM209() *KeyM209 {
+	pins := make([][]rune, 6)
+	positions := make([]rune, 6)
+	for i, wheel := range m209Wheels {
+		letters := []rune(wheel)
+		pins[i], _ = r.Keyword(letters, len(letters) / 2)
+		positions[i] = r.RuneFrom(letters)
+	}
+
+	lugs := make([][]int, 27)
+	for i := range lugs {
+		first := r.between(1, 6)
+		if second := r.Intn(7); second != first {
+			lugs[i] = []int{first, second}
+		} else {
+			lugs[i] = []int{first, 0}
+		}
+	}
+
+	return NewKeyM209(pins, lugs, positions)
+}
+
+func (r *Random) KeySolitaire() *KeySolitaire {
+	deck := make([]int, SolitaireJokerB)
+	for i := range deck {
+		deck[i] = i + 1
+	}
+
+	return NewKeySolitaire(utils.ShuffleRand(r.rand(), deck), nil)
+}
+
+func (r *Random) KeyZigzag(maxLines int) (*KeyZigzag, error) {
+	if err := randomSize("maximum of lines", maxLines, 2); err != nil {
+		return nil, err
+	}
+
+	return NewKeyZigzag(r.between(2, maxLines)), nil
+}
+
+func (r *Random) KeyScytale(maxLines int) (*KeyScytale, error) {
+	if err := randomSize("maximum of lines", maxLines, 2); err != nil {
+		return nil, err
+	}
+
+	return NewKeyScytale(r.between(2, maxLines)), nil
+}
+
+func (r *Random) KeyRoute(maxWidth int) (*KeyRoute, error) {
+	if err := randomSize("maximum width", maxWidth, 2); err != nil {
+		return nil, err
+	}
+
+	return NewKeyRoute(r.between(2, maxWidth), routes[r.Intn(len(routes))]), nil
+}
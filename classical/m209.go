@@ -0,0 +1,202 @@
+package classical
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var m209Wheels = [6]string{
+	"ABCDEFGHIJKLMNOPQRSTUVWXYZ",
+	"ABCDEFGHIJKLMNOPQRSTUVXYZ",
+	"ABCDEFGHIJKLMNOPQRSTUVX",
+	"ABCDEFGHIJKLMNOPQRSTU",
+	"ABCDEFGHIJKLMNOPQRS",
+	"ABCDEFGHIJKLMNOPQ",
+}
+
+// The pin read by the guide arm is ahead of the letter shown in the window.
+var m209Offsets = [6]int{15, 14, 13, 12, 11, 10}
+
+func NewKeyM209(pins [][]rune, lugs [][]int, positions []rune) *KeyM209 { return &KeyM209{Pins: pins, Lugs: lugs, Positions: positions} }
+func NewM209(text []rune, key *KeyM209) *M209 { return &M209{Cipher: &CipherClassical[KeyM209]{Text: text, Key: key}} }
+func EncryptM209(key *KeyM209, text []rune) ([]rune, error) { return cryptPure(verifyM209, checkM209, pureM209, key, text, true) }
+func DecryptM209(key *KeyM209, text []rune) ([]rune, error) { return cryptPure(verifyM209, checkM209, pureM209, key, text, false) }
+
+func pureM209(k *KeyM209, text []rune, encrypt bool, _ *Random) ([]rune, error) {
+	if encrypt {
+		return encryptM209(text, k)
+	}
+
+	return decryptM209(text, k)
+}
+
+// Pins lists the letters of the active pins of each of the six wheels. Each of the 27 bars has
+// up to two lugs given by the number of the wheel, from 1 to 6, they face. Positions are the
+// letters shown in the windows at the start of the message.
+type KeyM209 struct {
+	Pins [][]rune
+	Lugs [][]int
+	Positions []rune
+}
+
+type M209 struct { Cipher *CipherClassical[KeyM209] }
+func (c *M209) GetText() []rune { return c.Cipher.Text }
+func (c *M209) GetErrors() []error { return c.Cipher.Errors }
+func (c *M209) SetText(text []rune) { c.Cipher.Text = text }
+func (c *M209) Encrypt() { c.Cipher.crypt(pureM209, true) }
+func (c *M209) Decrypt() { c.Cipher.crypt(pureM209, false) }
+func (c *M209) EncryptE() error { return c.Cipher.cryptE(verifyM209, checkM209, true, c.Encrypt) }
+func (c *M209) DecryptE() error { return c.Cipher.cryptE(verifyM209, checkM209, false, c.Decrypt) }
+func (c *M209) Verify() bool { return c.Cipher.verify(verifyM209) }
+
+func verifyM209(k *KeyM209) []error {
+	errs := collectErrors(verifyLength("positions", k.Positions, 6))
+
+	if len(k.Pins) != 6 {
+		errs = append(errs, keyError("M-209 takes pins for 6 wheels, got %d", len(k.Pins)))
+	}
+
+	for i := 0; i < 6; i++ {
+		wheel := []rune(m209Wheels[i])
+		name := fmt.Sprintf("wheel %d", i + 1)
+
+		if i < len(k.Pins) && len(k.Pins[i]) > 0 {
+			errs = append(errs, collectErrors(verifyAlphabet(name + " pins", k.Pins[i]), verifyRunesIn(name + " pins", k.Pins[i], wheel))...)
+		}
+
+		if i < len(k.Positions) {
+			errs = append(errs, collectErrors(verifyRunesIn(name + " position", k.Positions[i:i + 1], wheel))...)
+		}
+	}
+
+	if len(k.Lugs) != 27 {
+		errs = append(errs, keyError("M-209 has 27 bars, got %d", len(k.Lugs)))
+	}
+
+	for i, bar := range k.Lugs {
+		wheels := make([]int, 0, 2)
+		for _, w := range bar {
+			if w < 0 || w > 6 {
+				errs = append(errs, keyError("bar %d has a lug on wheel %d", i + 1, w))
+			} else if w > 0 {
+				wheels = append(wheels, w)
+			}
+		}
+
+		if len(wheels) > 2 || len(wheels) == 2 && wheels[0] == wheels[1] {
+			errs = append(errs, keyError("bar %d has lugs %v, it takes two lugs on different wheels at most", i + 1, bar))
+		}
+	}
+
+	return errs
+}
+
+func checkM209(k *KeyM209, text []rune, encrypt bool) error { return checkAlphabet(text, []rune(AlphabetL + " ")) }
+
+// Lugs are written as the two wheel numbers of each bar separated by a dash, like 3-6 or 0-4.
+func ParseM209Lugs(bars []string) ([][]int, error) {
+	lugs := make([][]int, len(bars))
+
+	for i, bar := range bars {
+		for _, s := range strings.Split(bar, "-") {
+			w, err := strconv.Atoi(s)
+			if err != nil {
+				return nil, fmt.Errorf("%w: bar %d: %q is not a wheel number", ErrInvalidKey, i + 1, s)
+			}
+			lugs[i] = append(lugs[i], w)
+		}
+	}
+
+	return lugs, nil
+}
+
+// A dash stands for a wheel without active pins.
+func ParseM209Pins(wheels []string) [][]rune {
+	pins := make([][]rune, len(wheels))
+	for i, wheel := range wheels {
+		if wheel != "-" {
+			pins[i] = []rune(wheel)
+		}
+	}
+
+	return pins
+}
+
+// Each bar facing an active pin shifts the print wheel once, the letter is then the Beaufort
+// of the text with the shifted letter.
+func m209Keystream(k *KeyM209, length int) []rune {
+	pins := make([][]bool, 6)
+	positions := make([]int, 6)
+	for i, wheel := range m209Wheels {
+		amap := buildIndexMap([]rune(wheel))
+		pins[i] = make([]bool, len(wheel))
+		for _, r := range k.Pins[i] {
+			pins[i][amap[r]] = true
+		}
+		positions[i] = amap[k.Positions[i]]
+	}
+
+	alphabet := []rune(AlphabetL)
+	result := make([]rune, length)
+	active := make([]bool, 7)
+
+	for n := range result {
+		for i := range positions {
+			active[i + 1] = pins[i][(positions[i] + m209Offsets[i]) % len(pins[i])]
+			positions[i] = (positions[i] + 1) % len(pins[i])
+		}
+
+		count := 0
+		for _, bar := range k.Lugs {
+			for _, w := range bar {
+				if active[w] {
+					count++
+					break
+				}
+			}
+		}
+
+		result[n] = alphabet[(count + len(alphabet) - 1) % len(alphabet)]
+	}
+
+	return result
+}
+
+func encryptM209(text []rune, k *KeyM209) ([]rune, error) {
+	letters := make([]rune, len(text))
+	for i, r := range text {
+		if r == ' ' {
+			r = 'Z'
+		}
+		letters[i] = r
+	}
+
+	if err := checkAlphabet(letters, []rune(AlphabetL)); err != nil {
+		return nil, err
+	}
+
+	return []rune(ToSpaced(string(cryptBeaufort(letters, []rune(AlphabetL), m209Keystream(k, len(letters)))), 5)), nil
+}
+
+func decryptM209(text []rune, k *KeyM209) ([]rune, error) {
+	letters := make([]rune, 0, len(text))
+	for _, r := range text {
+		if r != ' ' {
+			letters = append(letters, r)
+		}
+	}
+
+	if err := checkAlphabet(letters, []rune(AlphabetL)); err != nil {
+		return nil, err
+	}
+
+	result := cryptBeaufort(letters, []rune(AlphabetL), m209Keystream(k, len(letters)))
+	for i, r := range result {
+		if r == 'Z' {
+			result[i] = ' '
+		}
+	}
+
+	return result, nil
+}
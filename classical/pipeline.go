@@ -0,0 +1,127 @@
+package classical
+
+import (
+	"cryptochev/utils"
+	"fmt"
+)
+
+func NewPipeline(text []rune, stages ...ICipherClassical) *Pipeline { return &Pipeline{Text: text, Stages: stages} }
+
+// Pipeline is not a CipherClassical, the stages hold their own keys and texts, so it has no key
+// which could be encoded or registered.
+type Pipeline struct {
+	Text []rune
+	Errors []error
+	Stages []ICipherClassical
+}
+
+func (c *Pipeline) GetText() []rune { return c.Text }
+func (c *Pipeline) GetErrors() []error {
+	errs := append([]error{}, c.Errors...)
+
+	for i, stage := range c.Stages {
+		if stage == nil {
+			continue
+		}
+
+		for _, err := range stage.GetErrors() {
+			errs = append(errs, fmt.Errorf("stage %d: %w", i, err))
+		}
+	}
+
+	return errs
+}
+func (c *Pipeline) SetText(text []rune) { c.Text = text }
+func (c *Pipeline) Encrypt() { c.Text = cryptPipeline(c.Text, c.Stages, true) }
+func (c *Pipeline) Decrypt() { c.Text = cryptPipeline(c.Text, c.Stages, false) }
+func (c *Pipeline) EncryptE() error { return c.cryptE(true) }
+func (c *Pipeline) DecryptE() error { return c.cryptE(false) }
+func (c *Pipeline) Verify() bool {
+	c.Errors = verifyPipeline(c.Stages)
+	valid := len(c.Errors) == 0
+
+	for _, stage := range c.Stages {
+		if stage != nil {
+			valid = stage.Verify() && valid
+		}
+	}
+
+	return valid
+}
+
+func verifyPipeline(stages []ICipherClassical) []error {
+	if len(stages) == 0 {
+		return []error{keyError("pipeline has no stages")}
+	}
+
+	errs := make([]error, 0)
+	for i, stage := range stages {
+		if stage == nil {
+			errs = append(errs, keyError("stage %d is missing", i))
+		}
+	}
+
+	return errs
+}
+
+func (c *Pipeline) cryptE(encrypt bool) error {
+	if errs := verifyPipeline(c.Stages); len(errs) > 0 {
+		return errs[0]
+	}
+
+	text, err := cryptPipelineE(c.Text, c.Stages, encrypt)
+	if err != nil {
+		return err
+	}
+
+	c.Text = text
+	return nil
+}
+
+func pipelineStage(stages []ICipherClassical, i int, encrypt bool) ICipherClassical {
+	if encrypt {
+		return stages[i]
+	}
+
+	return stages[len(stages) - i - 1]
+}
+
+func cryptPipeline(text []rune, stages []ICipherClassical, encrypt bool) []rune {
+	for i := range stages {
+		stage := pipelineStage(stages, i, encrypt)
+		stage.SetText(text)
+
+		if encrypt {
+			stage.Encrypt()
+		} else {
+			stage.Decrypt()
+		}
+
+		text = stage.GetText()
+	}
+
+	return text
+}
+
+func cryptPipelineE(text []rune, stages []ICipherClassical, encrypt bool) ([]rune, error) {
+	for i := range stages {
+		stage := pipelineStage(stages, i, encrypt)
+		stage.SetText(text)
+
+		var err error
+		if encrypt {
+			err = stage.EncryptE()
+		} else {
+			err = stage.DecryptE()
+		}
+
+		if err != nil {
+			index, _ := utils.SwapIf(i, len(stages) - i - 1, !encrypt)
+			return nil, fmt.Errorf("stage %d: %w", index, err)
+		}
+
+		text = stage.GetText()
+	}
+
+	return text, nil
+}
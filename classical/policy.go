@@ -0,0 +1,142 @@
+package classical
+
+import "unicode"
+
+type PolicyMode int
+const (
+	PolicyDrop PolicyMode = iota
+	PolicyPassthrough
+	PolicyReject
+)
+
+type Policy struct {
+	Mode PolicyMode
+	KeepCase bool
+	HoldKey bool
+}
+
+func NewPolicy(mode PolicyMode, keepCase, holdKey bool) *Policy { return &Policy{Mode: mode, KeepCase: keepCase, HoldKey: holdKey} }
+
+type policyRune struct {
+	at int
+	r rune
+}
+
+func (c *CipherClassical[K]) apply(alphabet []rune, positional bool, crypt func([]rune) ([]rune, error)) {
+	c.applyPolicy(alphabet, positional, func(text []rune) ([]rune, []int, error) {
+		crypted, err := crypt(text)
+		return crypted, nil, err
+	})
+}
+
+// Like apply for ciphers that insert runes, crypt also returns the index in its output of each
+// rune of its input, so that the runes put back by the policy follow the rune they followed.
+func (c *CipherClassical[K]) applyMapped(alphabet []rune, crypt func([]rune) ([]rune, []int)) {
+	c.applyPolicy(alphabet, false, func(text []rune) ([]rune, []int, error) {
+		crypted, positions := crypt(text)
+		return crypted, positions, nil
+	})
+}
+
+func (c *CipherClassical[K]) applyPolicy(alphabet []rune, positional bool, crypt func([]rune) ([]rune, []int, error)) {
+	if c.Policy == nil {
+		text, _, err := crypt(c.Text)
+		c.setText(text, err)
+		return
+	}
+
+	c.setText(applyPolicy(c.Policy, c.Text, alphabet, positional, crypt))
+}
+
+// Positional ciphers only depend on the index of a rune, so passthrough runes can advance
+// the key by being replaced with a placeholder which is dropped from the output. Their output
+// has the length of their input, positions is nil and the indices are kept.
+func applyPolicy(p *Policy, text, alphabet []rune, positional bool, crypt func([]rune) ([]rune, []int, error)) ([]rune, error) {
+	amap := buildIndexMap(alphabet)
+	kept := make([]rune, 0, len(text))
+	folded := make([]policyRune, 0)
+	passed := make([]policyRune, 0)
+	placeholder := positional && !p.HoldKey && len(alphabet) > 0
+
+	for i, r := range text {
+		if _, found := amap[r]; found {
+			kept = append(kept, r)
+			continue
+		}
+
+		if p.KeepCase {
+			if fr := foldRune(r, amap); fr != r {
+				folded = append(folded, policyRune{len(kept), r})
+				kept = append(kept, fr)
+				continue
+			}
+		}
+
+		switch p.Mode {
+		case PolicyReject:
+			return nil, &InvalidRuneError{Position: i, Rune: r}
+		case PolicyPassthrough:
+			passed = append(passed, policyRune{len(kept), r})
+			if placeholder {
+				kept = append(kept, alphabet[0])
+			}
+		}
+	}
+
+	crypted, positions, err := crypt(kept)
+	if err != nil {
+		return nil, err
+	}
+
+	at := func(i int) int {
+		if positions == nil {
+			return i
+		} else if i < len(positions) {
+			return positions[i]
+		}
+		return len(crypted)
+	}
+
+	for _, f := range folded {
+		if i := at(f.at); i < len(crypted) {
+			if unicode.IsLower(f.r) {
+				crypted[i] = unicode.ToLower(crypted[i])
+			} else {
+				crypted[i] = unicode.ToUpper(crypted[i])
+			}
+		}
+	}
+
+	if placeholder {
+		for _, p := range passed {
+			crypted[p.at] = p.r
+		}
+
+		return crypted, nil
+	}
+
+	result := make([]rune, 0, len(crypted) + len(passed))
+	pIndex := 0
+	for i, r := range crypted {
+		for ; pIndex < len(passed) && at(passed[pIndex].at) <= i; pIndex++ {
+			result = append(result, passed[pIndex].r)
+		}
+		result = append(result, r)
+	}
+
+	for ; pIndex < len(passed); pIndex++ {
+		result = append(result, passed[pIndex].r)
+	}
+
+	return result, nil
+}
+
+func foldRune(r rune, amap map[rune]int) rune {
+	if _, found := amap[unicode.ToUpper(r)]; found {
+		return unicode.ToUpper(r)
+	} else if _, found := amap[unicode.ToLower(r)]; found {
+		return unicode.ToLower(r)
+	}
+
+	return r
+}
@@ -4,6 +4,10 @@ import "unicode"
 
 func NewKeyVigenere(alphabet, key []rune) *KeyVigenere { return &KeyVigenere{Alphabet: alphabet, Key: key} }
 func NewVigenere(text []rune, key *KeyVigenere) *Vigenere { return &Vigenere{Cipher: &CipherClassical[KeyVigenere]{Text: text, Key: key}} }
+func EncryptVigenere(key *KeyVigenere, text []rune) ([]rune, error) { return cryptPure(verifyVigenere, checkVigenere, pureVigenere, key, text, true) }
+func DecryptVigenere(key *KeyVigenere, text []rune) ([]rune, error) { return cryptPure(verifyVigenere, checkVigenere, pureVigenere, key, text, false) }
+
+func pureVigenere(k *KeyVigenere, text []rune, encrypt bool, _ *Random) ([]rune, error) { return cryptVigenere(text, k.Alphabet, k.Key, encrypt), nil }
 
 type KeyVigenere struct {
 	Alphabet []rune
@@ -13,9 +17,21 @@ type KeyVigenere struct {
 type Vigenere struct { Cipher *CipherClassical[KeyVigenere] }
 func (c *Vigenere) GetText() []rune { return c.Cipher.Text }
 func (c *Vigenere) GetErrors() []error { return c.Cipher.Errors }
-func (c *Vigenere) Encrypt() { c.Cipher.Text = cryptVigenere(c.Cipher.Text, c.Cipher.Key.Alphabet, c.Cipher.Key.Key, true) }
-func (c *Vigenere) Decrypt() { c.Cipher.Text = cryptVigenere(c.Cipher.Text, c.Cipher.Key.Alphabet, c.Cipher.Key.Key, false) }
-func (c *Vigenere) Verify() bool { return true }
+func (c *Vigenere) SetText(text []rune) { c.Cipher.Text = text }
+func (c *Vigenere) Encrypt() { c.Cipher.apply(c.Cipher.Key.Alphabet, true, c.Cipher.pure(pureVigenere, true)) }
+func (c *Vigenere) Decrypt() { c.Cipher.apply(c.Cipher.Key.Alphabet, true, c.Cipher.pure(pureVigenere, false)) }
+func (c *Vigenere) EncryptE() error { return c.Cipher.cryptE(verifyVigenere, checkVigenere, true, c.Encrypt) }
+func (c *Vigenere) DecryptE() error { return c.Cipher.cryptE(verifyVigenere, checkVigenere, false, c.Decrypt) }
+func (c *Vigenere) Verify() bool { return c.Cipher.verify(verifyVigenere) }
+
+func verifyVigenere(k *KeyVigenere) []error {
+	return collectErrors(
+		verifyAlphabet("alphabet", k.Alphabet),
+		verifyRunesIn("key", k.Key, k.Alphabet),
+	)
+}
+
+func checkVigenere(k *KeyVigenere, text []rune, encrypt bool) error { return checkAlphabet(text, k.Alphabet) }
 
 func cryptVigenere(text, alphabet, key []rune, encrypt bool) []rune {
 	if len(key) == 0 {
@@ -37,13 +53,20 @@ func cryptVigenere(text, alphabet, key []rune, encrypt bool) []rune {
 }
 
 func NewVigenereBeaufort(text []rune, key *KeyVigenere) *VigenereBeaufort { return &VigenereBeaufort{Cipher: &CipherClassical[KeyVigenere]{Text: text, Key: key}} }
+func EncryptVigenereBeaufort(key *KeyVigenere, text []rune) ([]rune, error) { return cryptPure(verifyVigenere, checkVigenere, pureVigenereBeaufort, key, text, true) }
+func DecryptVigenereBeaufort(key *KeyVigenere, text []rune) ([]rune, error) { return cryptPure(verifyVigenere, checkVigenere, pureVigenereBeaufort, key, text, false) }
+
+func pureVigenereBeaufort(k *KeyVigenere, text []rune, encrypt bool, _ *Random) ([]rune, error) { return cryptVigenere(text, k.Alphabet, k.Key, !encrypt), nil }
 
 type VigenereBeaufort struct { Cipher *CipherClassical[KeyVigenere] }
 func (c *VigenereBeaufort) GetText() []rune { return c.Cipher.Text }
 func (c *VigenereBeaufort) GetErrors() []error { return c.Cipher.Errors }
-func (c *VigenereBeaufort) Encrypt() { c.Cipher.Text = cryptVigenere(c.Cipher.Text, c.Cipher.Key.Alphabet, c.Cipher.Key.Key, false) }
-func (c *VigenereBeaufort) Decrypt() { c.Cipher.Text = cryptVigenere(c.Cipher.Text, c.Cipher.Key.Alphabet, c.Cipher.Key.Key, true) }
-func (c *VigenereBeaufort) Verify() bool { return true }
+func (c *VigenereBeaufort) SetText(text []rune) { c.Cipher.Text = text }
+func (c *VigenereBeaufort) Encrypt() { c.Cipher.apply(c.Cipher.Key.Alphabet, true, c.Cipher.pure(pureVigenereBeaufort, true)) }
+func (c *VigenereBeaufort) Decrypt() { c.Cipher.apply(c.Cipher.Key.Alphabet, true, c.Cipher.pure(pureVigenereBeaufort, false)) }
+func (c *VigenereBeaufort) EncryptE() error { return c.Cipher.cryptE(verifyVigenere, checkVigenere, true, c.Encrypt) }
+func (c *VigenereBeaufort) DecryptE() error { return c.Cipher.cryptE(verifyVigenere, checkVigenere, false, c.Decrypt) }
+func (c *VigenereBeaufort) Verify() bool { return c.Cipher.verify(verifyVigenere) }
 
 func gronsfeldToVigenereKey(alphabet, key []rune) []rune {
 	keyv := make([]rune, 0, len(key))
@@ -58,16 +81,46 @@ func gronsfeldToVigenereKey(alphabet, key []rune) []rune {
 }
 
 func NewVigenereGronsfeld(text []rune, key *KeyVigenere) *VigenereGronsfeld { return &VigenereGronsfeld{Cipher: &CipherClassical[KeyVigenere]{Text: text, Key: key}} }
+func EncryptVigenereGronsfeld(key *KeyVigenere, text []rune) ([]rune, error) { return cryptPure(verifyGronsfeld, checkVigenere, pureVigenereGronsfeld, key, text, true) }
+func DecryptVigenereGronsfeld(key *KeyVigenere, text []rune) ([]rune, error) { return cryptPure(verifyGronsfeld, checkVigenere, pureVigenereGronsfeld, key, text, false) }
+
+func pureVigenereGronsfeld(k *KeyVigenere, text []rune, encrypt bool, _ *Random) ([]rune, error) { return cryptVigenere(text, k.Alphabet, gronsfeldToVigenereKey(k.Alphabet, k.Key), encrypt), nil }
 
 type VigenereGronsfeld struct { Cipher *CipherClassical[KeyVigenere] }
 func (c *VigenereGronsfeld) GetText() []rune { return c.Cipher.Text }
 func (c *VigenereGronsfeld) GetErrors() []error { return c.Cipher.Errors }
-func (c *VigenereGronsfeld) Encrypt() { c.Cipher.Text = cryptVigenere(c.Cipher.Text, c.Cipher.Key.Alphabet, gronsfeldToVigenereKey(c.Cipher.Key.Alphabet, c.Cipher.Key.Key), true) }
-func (c *VigenereGronsfeld) Decrypt() { c.Cipher.Text = cryptVigenere(c.Cipher.Text, c.Cipher.Key.Alphabet, gronsfeldToVigenereKey(c.Cipher.Key.Alphabet, c.Cipher.Key.Key), false) }
-func (c *VigenereGronsfeld) Verify() bool { return true }
+func (c *VigenereGronsfeld) SetText(text []rune) { c.Cipher.Text = text }
+func (c *VigenereGronsfeld) Encrypt() { c.Cipher.apply(c.Cipher.Key.Alphabet, true, c.Cipher.pure(pureVigenereGronsfeld, true)) }
+func (c *VigenereGronsfeld) Decrypt() { c.Cipher.apply(c.Cipher.Key.Alphabet, true, c.Cipher.pure(pureVigenereGronsfeld, false)) }
+func (c *VigenereGronsfeld) EncryptE() error { return c.Cipher.cryptE(verifyGronsfeld, checkVigenere, true, c.Encrypt) }
+func (c *VigenereGronsfeld) DecryptE() error { return c.Cipher.cryptE(verifyGronsfeld, checkVigenere, false, c.Decrypt) }
+func (c *VigenereGronsfeld) Verify() bool { return c.Cipher.verify(verifyGronsfeld) }
+
+func verifyGronsfeld(k *KeyVigenere) []error {
+	errs := collectErrors(verifyAlphabet("alphabet", k.Alphabet))
+
+	for _, r := range k.Key {
+		if !unicode.IsDigit(r) || int(r - '0') >= len(k.Alphabet) {
+			errs = append(errs, keyError("key contains %q which is not a digit below %d", r, len(k.Alphabet)))
+			break
+		}
+	}
+
+	return errs
+}
 
 func NewKeyAutokey(alphabet, primer []rune) *KeyAutokey { return &KeyAutokey{Alphabet: alphabet, Primer: primer} }
 func NewAutokey(text []rune, key *KeyAutokey) *Autokey { return &Autokey{Cipher: &CipherClassical[KeyAutokey]{Text: text, Key: key}} }
+func EncryptAutokey(key *KeyAutokey, text []rune) ([]rune, error) { return cryptPure(verifyAutokey, checkAutokey, pureAutokey, key, text, true) }
+func DecryptAutokey(key *KeyAutokey, text []rune) ([]rune, error) { return cryptPure(verifyAutokey, checkAutokey, pureAutokey, key, text, false) }
+
+func pureAutokey(k *KeyAutokey, text []rune, encrypt bool, _ *Random) ([]rune, error) {
+	if encrypt {
+		return cryptVigenere(text, k.Alphabet, append(append([]rune{}, k.Primer...), text...), true), nil
+	}
+
+	return decryptAutokey(text, k.Alphabet, k.Primer), nil
+}
 
 type KeyAutokey struct {
 	Alphabet []rune
@@ -77,9 +130,22 @@ type KeyAutokey struct {
 type Autokey struct { Cipher *CipherClassical[KeyAutokey] }
 func (c *Autokey) GetText() []rune { return c.Cipher.Text }
 func (c *Autokey) GetErrors() []error { return c.Cipher.Errors }
-func (c *Autokey) Encrypt() { c.Cipher.Text = cryptVigenere(c.Cipher.Text, c.Cipher.Key.Alphabet, append(c.Cipher.Key.Primer, c.Cipher.Text...), true) }
-func (c *Autokey) Decrypt() { c.Cipher.Text = decryptAutokey(c.Cipher.Text, c.Cipher.Key.Alphabet, c.Cipher.Key.Primer) }
-func (c *Autokey) Verify() bool { return true }
+func (c *Autokey) SetText(text []rune) { c.Cipher.Text = text }
+func (c *Autokey) Encrypt() { c.Cipher.apply(c.Cipher.Key.Alphabet, false, c.Cipher.pure(pureAutokey, true)) }
+func (c *Autokey) Decrypt() { c.Cipher.apply(c.Cipher.Key.Alphabet, false, c.Cipher.pure(pureAutokey, false)) }
+func (c *Autokey) EncryptE() error { return c.Cipher.cryptE(verifyAutokey, checkAutokey, true, c.Encrypt) }
+func (c *Autokey) DecryptE() error { return c.Cipher.cryptE(verifyAutokey, checkAutokey, false, c.Decrypt) }
+func (c *Autokey) Verify() bool { return c.Cipher.verify(verifyAutokey) }
+
+func verifyAutokey(k *KeyAutokey) []error {
+	return collectErrors(
+		verifyAlphabet("alphabet", k.Alphabet),
+		verifyNotEmpty("primer", k.Primer),
+		verifyRunesIn("primer", k.Primer, k.Alphabet),
+	)
+}
+
+func checkAutokey(k *KeyAutokey, text []rune, encrypt bool) error { return checkAlphabet(text, k.Alphabet) }
 
 func decryptAutokey(text, alphabet, primer []rune) []rune {
 	result := make([]rune, len(text))
@@ -97,6 +163,10 @@ func decryptAutokey(text, alphabet, primer []rune) []rune {
 
 func NewKeyBeaufort(alphabet, key []rune) *KeyBeaufort { return &KeyBeaufort{Alphabet: alphabet, Key: key} }
 func NewBeaufort(text []rune, key *KeyBeaufort) *Beaufort { return &Beaufort{Cipher: &CipherClassical[KeyBeaufort]{Text: text, Key: key}} }
+func EncryptBeaufort(key *KeyBeaufort, text []rune) ([]rune, error) { return cryptPure(verifyBeaufort, checkBeaufort, pureBeaufort, key, text, true) }
+func DecryptBeaufort(key *KeyBeaufort, text []rune) ([]rune, error) { return cryptPure(verifyBeaufort, checkBeaufort, pureBeaufort, key, text, false) }
+
+func pureBeaufort(k *KeyBeaufort, text []rune, encrypt bool, _ *Random) ([]rune, error) { return cryptBeaufort(text, k.Alphabet, k.Key), nil }
 
 type KeyBeaufort struct {
 	Alphabet []rune
@@ -106,9 +176,21 @@ type KeyBeaufort struct {
 type Beaufort struct { Cipher *CipherClassical[KeyBeaufort] }
 func (c *Beaufort) GetText() []rune { return c.Cipher.Text }
 func (c *Beaufort) GetErrors() []error { return c.Cipher.Errors }
-func (c *Beaufort) Encrypt() { c.Cipher.Text = cryptBeaufort(c.Cipher.Text, c.Cipher.Key.Alphabet, c.Cipher.Key.Key) }
-func (c *Beaufort) Decrypt() { c.Cipher.Text = cryptBeaufort(c.Cipher.Text, c.Cipher.Key.Alphabet, c.Cipher.Key.Key) }
-func (c *Beaufort) Verify() bool { return true }
+func (c *Beaufort) SetText(text []rune) { c.Cipher.Text = text }
+func (c *Beaufort) Encrypt() { c.Cipher.apply(c.Cipher.Key.Alphabet, true, c.Cipher.pure(pureBeaufort, true)) }
+func (c *Beaufort) Decrypt() { c.Cipher.apply(c.Cipher.Key.Alphabet, true, c.Cipher.pure(pureBeaufort, false)) }
+func (c *Beaufort) EncryptE() error { return c.Cipher.cryptE(verifyBeaufort, checkBeaufort, true, c.Encrypt) }
+func (c *Beaufort) DecryptE() error { return c.Cipher.cryptE(verifyBeaufort, checkBeaufort, false, c.Decrypt) }
+func (c *Beaufort) Verify() bool { return c.Cipher.verify(verifyBeaufort) }
+
+func verifyBeaufort(k *KeyBeaufort) []error {
+	return collectErrors(
+		verifyAlphabet("alphabet", k.Alphabet),
+		verifyRunesIn("key", k.Key, k.Alphabet),
+	)
+}
+
+func checkBeaufort(k *KeyBeaufort, text []rune, encrypt bool) error { return checkAlphabet(text, k.Alphabet) }
 
 func cryptBeaufort(text, alphabet, key []rune) []rune {
 	if len(key) == 0 {
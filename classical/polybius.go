@@ -1,7 +1,27 @@
 package classical
 
+import (
+	"math"
+	"strconv"
+	"unicode"
+)
+
 func NewKeyPolybius(alphabet, header []rune) *KeyPolybius { return &KeyPolybius{Alphabet: alphabet, Header: header} }
 func NewPolybius(text []rune, key *KeyPolybius) *Polybius { return &Polybius{Cipher: &CipherClassical[KeyPolybius]{Text: text, Key: key}} }
+func EncryptPolybius(key *KeyPolybius, text []rune) ([]rune, error) { return cryptPure(verifyPolybius, checkPolybius, purePolybius, key, text, true) }
+func DecryptPolybius(key *KeyPolybius, text []rune) ([]rune, error) { return cryptPure(verifyPolybius, checkPolybius, purePolybius, key, text, false) }
+
+func purePolybius(k *KeyPolybius, text []rune, encrypt bool, _ *Random) ([]rune, error) {
+	if err := checkPolybius(k, text, encrypt); err != nil {
+		return nil, err
+	}
+
+	if encrypt {
+		return encryptPolybius(text, k.Alphabet, k.Header), nil
+	}
+
+	return decryptPolybius(text, k.Alphabet, k.Header), nil
+}
 
 type KeyPolybius struct {
 	Alphabet []rune
@@ -11,9 +31,41 @@ type KeyPolybius struct {
 type Polybius struct { Cipher *CipherClassical[KeyPolybius] }
 func (c *Polybius) GetText() []rune    { return c.Cipher.Text }
 func (c *Polybius) GetErrors() []error { return c.Cipher.Errors }
-func (c *Polybius) Encrypt() { c.Cipher.Text = encryptPolybius(c.Cipher.Text, c.Cipher.Key.Alphabet, c.Cipher.Key.Header) }
-func (c *Polybius) Decrypt() { c.Cipher.Text = decryptPolybius(c.Cipher.Text, c.Cipher.Key.Alphabet, c.Cipher.Key.Header) }
-func (c *Polybius) Verify() bool { return true }
+func (c *Polybius) SetText(text []rune) { c.Cipher.Text = text }
+func (c *Polybius) Encrypt() { c.Cipher.crypt(purePolybius, true) }
+func (c *Polybius) Decrypt() { c.Cipher.crypt(purePolybius, false) }
+func (c *Polybius) EncryptE() error { return c.Cipher.cryptE(verifyPolybius, checkPolybius, true, c.Encrypt) }
+func (c *Polybius) DecryptE() error { return c.Cipher.cryptE(verifyPolybius, checkPolybius, false, c.Decrypt) }
+func (c *Polybius) Verify() bool { return c.Cipher.verify(verifyPolybius) }
+
+func verifyPolybius(k *KeyPolybius) []error {
+	errs := collectErrors(
+		verifyAlphabet("alphabet", k.Alphabet),
+		verifyAlphabet("header", k.Header),
+	)
+
+	if len(k.Alphabet) > len(k.Header) * len(k.Header) {
+		errs = append(errs, keyError("alphabet of %d runes does not fit a %dx%d square", len(k.Alphabet), len(k.Header), len(k.Header)))
+	}
+
+	return errs
+}
+
+func checkPolybius(k *KeyPolybius, text []rune, encrypt bool) error {
+	if encrypt {
+		return checkAlphabet(text, k.Alphabet)
+	}
+
+	return checkPolybiusHeader(text, k.Header)
+}
+
+func checkPolybiusHeader(text, header []rune) error {
+	if err := checkLength(text, 2); err != nil {
+		return err
+	}
+
+	return checkAlphabet(text, header)
+}
 
 func encryptPolybius(text, alphabet, header []rune) []rune {
 	amap := buildIndexMap(alphabet)
@@ -40,6 +92,20 @@ func decryptPolybius(text, alphabet, header []rune) []rune {
 
 func NewKeyADFGX(alphabet, key []rune) *KeyADFGX { return &KeyADFGX{Alphabet: alphabet, Key: key} }
 func NewADFGX(text []rune, key *KeyADFGX) *ADFGX { return &ADFGX{Cipher: &CipherClassical[KeyADFGX]{Text: text, Key: key}} }
+func EncryptADFGX(key *KeyADFGX, text []rune) ([]rune, error) { return cryptPure(verifyADFGX, checkADFGX, pureADFGX, key, text, true) }
+func DecryptADFGX(key *KeyADFGX, text []rune) ([]rune, error) { return cryptPure(verifyADFGX, checkADFGX, pureADFGX, key, text, false) }
+
+func pureADFGX(k *KeyADFGX, text []rune, encrypt bool, _ *Random) ([]rune, error) {
+	if err := checkADFGX(k, text, encrypt); err != nil {
+		return nil, err
+	}
+
+	if encrypt {
+		return encryptADFGX(text, k.Alphabet, k.Key), nil
+	}
+
+	return decryptADFGX(text, k.Alphabet, k.Key), nil
+}
 
 type KeyADFGX struct {
 	Alphabet []rune
@@ -49,9 +115,28 @@ type KeyADFGX struct {
 type ADFGX struct { Cipher *CipherClassical[KeyADFGX] }
 func (c *ADFGX) GetText() []rune    { return c.Cipher.Text }
 func (c *ADFGX) GetErrors() []error { return c.Cipher.Errors }
-func (c *ADFGX) Encrypt() { c.Cipher.Text = encryptADFGX(c.Cipher.Text, c.Cipher.Key.Alphabet, c.Cipher.Key.Key) }
-func (c *ADFGX) Decrypt() { c.Cipher.Text = decryptADFGX(c.Cipher.Text, c.Cipher.Key.Alphabet, c.Cipher.Key.Key) }
-func (c *ADFGX) Verify() bool { return true }
+func (c *ADFGX) SetText(text []rune) { c.Cipher.Text = text }
+func (c *ADFGX) Encrypt() { c.Cipher.crypt(pureADFGX, true) }
+func (c *ADFGX) Decrypt() { c.Cipher.crypt(pureADFGX, false) }
+func (c *ADFGX) EncryptE() error { return c.Cipher.cryptE(verifyADFGX, checkADFGX, true, c.Encrypt) }
+func (c *ADFGX) DecryptE() error { return c.Cipher.cryptE(verifyADFGX, checkADFGX, false, c.Decrypt) }
+func (c *ADFGX) Verify() bool { return c.Cipher.verify(verifyADFGX) }
+
+func verifyADFGX(k *KeyADFGX) []error {
+	return collectErrors(
+		verifyAlphabet("alphabet", k.Alphabet),
+		verifyLength("alphabet", k.Alphabet, 25),
+		verifyNotEmpty("key", k.Key),
+	)
+}
+
+func checkADFGX(k *KeyADFGX, text []rune, encrypt bool) error {
+	if encrypt {
+		return checkAlphabet(text, k.Alphabet)
+	}
+
+	return checkPolybiusHeader(text, []rune("ADFGX"))
+}
 
 func encryptADFGX(text, alphabet, key []rune) []rune {
 	result := encryptPolybius(text, alphabet, []rune("ADFGX"))
@@ -65,6 +150,20 @@ func decryptADFGX(text, alphabet, key []rune) []rune {
 
 func NewKeyADFGVX(alphabet, key []rune) *KeyADFGVX { return &KeyADFGVX{Alphabet: alphabet, Key: key} }
 func NewADFGVX(text []rune, key *KeyADFGVX) *ADFGVX { return &ADFGVX{Cipher: &CipherClassical[KeyADFGVX]{Text: text, Key: key}} }
+func EncryptADFGVX(key *KeyADFGVX, text []rune) ([]rune, error) { return cryptPure(verifyADFGVX, checkADFGVX, pureADFGVX, key, text, true) }
+func DecryptADFGVX(key *KeyADFGVX, text []rune) ([]rune, error) { return cryptPure(verifyADFGVX, checkADFGVX, pureADFGVX, key, text, false) }
+
+func pureADFGVX(k *KeyADFGVX, text []rune, encrypt bool, _ *Random) ([]rune, error) {
+	if err := checkADFGVX(k, text, encrypt); err != nil {
+		return nil, err
+	}
+
+	if encrypt {
+		return encryptADFGVX(text, k.Alphabet, k.Key), nil
+	}
+
+	return decryptADFGVX(text, k.Alphabet, k.Key), nil
+}
 
 type KeyADFGVX struct {
 	Alphabet []rune
@@ -74,9 +173,28 @@ type KeyADFGVX struct {
 type ADFGVX struct { Cipher *CipherClassical[KeyADFGVX] }
 func (c *ADFGVX) GetText() []rune    { return c.Cipher.Text }
 func (c *ADFGVX) GetErrors() []error { return c.Cipher.Errors }
-func (c *ADFGVX) Encrypt() {  c.Cipher.Text = encryptADFGVX(c.Cipher.Text, c.Cipher.Key.Alphabet, c.Cipher.Key.Key) }
-func (c *ADFGVX) Decrypt() { c.Cipher.Text = decryptADFGVX(c.Cipher.Text, c.Cipher.Key.Alphabet, c.Cipher.Key.Key) }
-func (c *ADFGVX) Verify() bool { return true }
+func (c *ADFGVX) SetText(text []rune) { c.Cipher.Text = text }
+func (c *ADFGVX) Encrypt() { c.Cipher.crypt(pureADFGVX, true) }
+func (c *ADFGVX) Decrypt() { c.Cipher.crypt(pureADFGVX, false) }
+func (c *ADFGVX) EncryptE() error { return c.Cipher.cryptE(verifyADFGVX, checkADFGVX, true, c.Encrypt) }
+func (c *ADFGVX) DecryptE() error { return c.Cipher.cryptE(verifyADFGVX, checkADFGVX, false, c.Decrypt) }
+func (c *ADFGVX) Verify() bool { return c.Cipher.verify(verifyADFGVX) }
+
+func verifyADFGVX(k *KeyADFGVX) []error {
+	return collectErrors(
+		verifyAlphabet("alphabet", k.Alphabet),
+		verifyLength("alphabet", k.Alphabet, 36),
+		verifyNotEmpty("key", k.Key),
+	)
+}
+
+func checkADFGVX(k *KeyADFGVX, text []rune, encrypt bool) error {
+	if encrypt {
+		return checkAlphabet(text, k.Alphabet)
+	}
+
+	return checkPolybiusHeader(text, []rune("ADFGVX"))
+}
 
 func encryptADFGVX(text, alphabet, key []rune) []rune {
 	result := encryptPolybius(text, alphabet, []rune("ADFGVX"))
@@ -86,4 +204,277 @@ func encryptADFGVX(text, alphabet, key []rune) []rune {
 func decryptADFGVX(text, alphabet, key []rune) []rune {
 	result := cryptColumn(text, key, false)
 	return decryptPolybius(result, alphabet, []rune("ADFGVX"))
-}
\ No newline at end of file
+}
+func NewKeyBifid(alphabet []rune, period int) *KeyBifid { return &KeyBifid{Alphabet: alphabet, Period: period} }
+func NewBifid(text []rune, key *KeyBifid) *Bifid { return &Bifid{Cipher: &CipherClassical[KeyBifid]{Text: text, Key: key}} }
+func EncryptBifid(key *KeyBifid, text []rune) ([]rune, error) { return cryptPure(verifyBifid, checkBifid, pureBifid, key, text, true) }
+func DecryptBifid(key *KeyBifid, text []rune) ([]rune, error) { return cryptPure(verifyBifid, checkBifid, pureBifid, key, text, false) }
+
+func pureBifid(k *KeyBifid, text []rune, encrypt bool, _ *Random) ([]rune, error) { return cryptBifid(text, k.Alphabet, k.Period, encrypt), nil }
+
+type KeyBifid struct {
+	Alphabet []rune
+	Period   int
+}
+
+type Bifid struct { Cipher *CipherClassical[KeyBifid] }
+func (c *Bifid) GetText() []rune    { return c.Cipher.Text }
+func (c *Bifid) GetErrors() []error { return c.Cipher.Errors }
+func (c *Bifid) SetText(text []rune) { c.Cipher.Text = text }
+func (c *Bifid) Encrypt() { c.Cipher.apply(c.Cipher.Key.Alphabet, false, c.Cipher.pure(pureBifid, true)) }
+func (c *Bifid) Decrypt() { c.Cipher.apply(c.Cipher.Key.Alphabet, false, c.Cipher.pure(pureBifid, false)) }
+func (c *Bifid) EncryptE() error { return c.Cipher.cryptE(verifyBifid, checkBifid, true, c.Encrypt) }
+func (c *Bifid) DecryptE() error { return c.Cipher.cryptE(verifyBifid, checkBifid, false, c.Decrypt) }
+func (c *Bifid) Verify() bool { return c.Cipher.verify(verifyBifid) }
+
+func verifyBifid(k *KeyBifid) []error {
+	return collectErrors(
+		verifyAlphabet("alphabet", k.Alphabet),
+		verifySquare("alphabet", k.Alphabet),
+		verifyMin("period", k.Period, 0),
+	)
+}
+
+func checkBifid(k *KeyBifid, text []rune, encrypt bool) error { return checkAlphabet(text, k.Alphabet) }
+
+// The header holds the coordinates themselves, so the Polybius output is the row and column of each rune.
+func polybiusCoordinates(alphabet []rune) []rune {
+	header := make([]rune, int(math.Sqrt(float64(len(alphabet)))))
+	for i := range header {
+		header[i] = rune(i)
+	}
+
+	return header
+}
+
+// A period of 0 fractionates the whole text as a single block.
+func cryptBifid(text, alphabet []rune, period int, encrypt bool) []rune {
+	header := polybiusCoordinates(alphabet)
+	coords := fractionate(encryptPolybius(text, alphabet, header), 2, period, encrypt)
+
+	return decryptPolybius(coords, alphabet, header)
+}
+
+// Coordinates hold width values per rune. Encryption writes each coordinate of a block on its
+// own line and reads the lines back in groups of width, decryption does the opposite.
+func fractionate(coords []rune, width, period int, encrypt bool) []rune {
+	result := make([]rune, len(coords))
+	runes := len(coords) / width
+
+	if period <= 0 {
+		period = runes
+	}
+
+	for start := 0; start < runes; start += period {
+		n := period
+		if start + n > runes {
+			n = runes - start
+		}
+
+		block := coords[start * width:(start + n) * width]
+		fractionated := result[start * width:(start + n) * width]
+
+		for i := 0; i < n; i++ {
+			for d := 0; d < width; d++ {
+				if encrypt {
+					fractionated[d * n + i] = block[i * width + d]
+				} else {
+					fractionated[i * width + d] = block[d * n + i]
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+func NewKeyTrifid(alphabet []rune, period int) *KeyTrifid { return &KeyTrifid{Alphabet: alphabet, Period: period} }
+func NewTrifid(text []rune, key *KeyTrifid) *Trifid { return &Trifid{Cipher: &CipherClassical[KeyTrifid]{Text: text, Key: key}} }
+func EncryptTrifid(key *KeyTrifid, text []rune) ([]rune, error) { return cryptPure(verifyTrifid, checkTrifid, pureTrifid, key, text, true) }
+func DecryptTrifid(key *KeyTrifid, text []rune) ([]rune, error) { return cryptPure(verifyTrifid, checkTrifid, pureTrifid, key, text, false) }
+
+func pureTrifid(k *KeyTrifid, text []rune, encrypt bool, _ *Random) ([]rune, error) { return cryptTrifid(text, k.Alphabet, k.Period, encrypt), nil }
+
+type KeyTrifid struct {
+	Alphabet []rune
+	Period   int
+}
+
+type Trifid struct { Cipher *CipherClassical[KeyTrifid] }
+func (c *Trifid) GetText() []rune    { return c.Cipher.Text }
+func (c *Trifid) GetErrors() []error { return c.Cipher.Errors }
+func (c *Trifid) SetText(text []rune) { c.Cipher.Text = text }
+func (c *Trifid) Encrypt() { c.Cipher.apply(c.Cipher.Key.Alphabet, false, c.Cipher.pure(pureTrifid, true)) }
+func (c *Trifid) Decrypt() { c.Cipher.apply(c.Cipher.Key.Alphabet, false, c.Cipher.pure(pureTrifid, false)) }
+func (c *Trifid) EncryptE() error { return c.Cipher.cryptE(verifyTrifid, checkTrifid, true, c.Encrypt) }
+func (c *Trifid) DecryptE() error { return c.Cipher.cryptE(verifyTrifid, checkTrifid, false, c.Decrypt) }
+func (c *Trifid) Verify() bool { return c.Cipher.verify(verifyTrifid) }
+
+func verifyTrifid(k *KeyTrifid) []error {
+	return collectErrors(
+		verifyAlphabet("alphabet", k.Alphabet),
+		verifyLength("alphabet", k.Alphabet, 27),
+		verifyMin("period", k.Period, 0),
+	)
+}
+
+func checkTrifid(k *KeyTrifid, text []rune, encrypt bool) error { return checkAlphabet(text, k.Alphabet) }
+
+// Each rune of the 27 rune cube is found by its layer, row and column.
+func cryptTrifid(text, alphabet []rune, period int, encrypt bool) []rune {
+	amap := buildIndexMap(alphabet)
+	coords := make([]rune, 0, len(text) * 3)
+
+	for _, r := range text {
+		i := amap[r]
+		coords = append(coords, rune(i / 9), rune(i / 3 % 3), rune(i % 3))
+	}
+
+	coords = fractionate(coords, 3, period, encrypt)
+	result := make([]rune, len(text))
+
+	for i := range result {
+		result[i] = alphabet[coords[i * 3] * 9 + coords[i * 3 + 1] * 3 + coords[i * 3 + 2]]
+	}
+
+	return result
+}
+
+func NewKeyNihilist(alphabet, header, key []rune) *KeyNihilist { return &KeyNihilist{Alphabet: alphabet, Header: header, Key: key} }
+func NewNihilist(text []rune, key *KeyNihilist) *Nihilist { return &Nihilist{Cipher: &CipherClassical[KeyNihilist]{Text: text, Key: key}} }
+func EncryptNihilist(key *KeyNihilist, text []rune) ([]rune, error) { return cryptPure(verifyNihilist, checkNihilist, pureNihilist, key, text, true) }
+func DecryptNihilist(key *KeyNihilist, text []rune) ([]rune, error) { return cryptPure(verifyNihilist, checkNihilist, pureNihilist, key, text, false) }
+
+func pureNihilist(k *KeyNihilist, text []rune, encrypt bool, _ *Random) ([]rune, error) {
+	if encrypt {
+		return encryptNihilist(text, k.Alphabet, k.Header, k.Key)
+	}
+
+	return decryptNihilist(text, k.Alphabet, k.Header, k.Key)
+}
+
+// The header of the Polybius square must be made of digits, which give the two digit number of each rune.
+type KeyNihilist struct {
+	Alphabet []rune
+	Header   []rune
+	Key      []rune
+}
+
+type Nihilist struct { Cipher *CipherClassical[KeyNihilist] }
+func (c *Nihilist) GetText() []rune    { return c.Cipher.Text }
+func (c *Nihilist) GetErrors() []error { return c.Cipher.Errors }
+func (c *Nihilist) SetText(text []rune) { c.Cipher.Text = text }
+func (c *Nihilist) Encrypt() { c.Cipher.crypt(pureNihilist, true) }
+func (c *Nihilist) Decrypt() { c.Cipher.crypt(pureNihilist, false) }
+func (c *Nihilist) EncryptE() error { return c.Cipher.cryptE(verifyNihilist, checkNihilist, true, c.Encrypt) }
+func (c *Nihilist) DecryptE() error { return c.Cipher.cryptE(verifyNihilist, checkNihilist, false, c.Decrypt) }
+func (c *Nihilist) Verify() bool { return c.Cipher.verify(verifyNihilist) }
+
+func verifyNihilist(k *KeyNihilist) []error {
+	errs := verifyPolybius(&KeyPolybius{Alphabet: k.Alphabet, Header: k.Header})
+	errs = append(errs, collectErrors(
+		verifyRunesIn("header", k.Header, []rune("0123456789")),
+		verifyNotEmpty("key", k.Key),
+		verifyRunesIn("key", k.Key, k.Alphabet),
+	)...)
+
+	return errs
+}
+
+func checkNihilist(k *KeyNihilist, text []rune, encrypt bool) error {
+	if encrypt {
+		return checkAlphabet(text, k.Alphabet)
+	}
+
+	_, err := ParseNumbers(text)
+	return err
+}
+
+// Numbers are rendered in decimal and separated by a single space.
+func FormatNumbers(numbers []int) []rune {
+	result := make([]rune, 0, len(numbers) * 3)
+
+	for i, n := range numbers {
+		if i > 0 {
+			result = append(result, ' ')
+		}
+		result = append(result, []rune(strconv.Itoa(n))...)
+	}
+
+	return result
+}
+
+// Any run of spaces separates two numbers, other runes than digits are rejected.
+func ParseNumbers(text []rune) ([]int, error) {
+	numbers := make([]int, 0, len(text) / 3 + 1)
+	number := -1
+
+	for i, r := range text {
+		if unicode.IsSpace(r) {
+			if number >= 0 {
+				numbers = append(numbers, number)
+				number = -1
+			}
+		} else if r >= '0' && r <= '9' {
+			if number < 0 {
+				number = 0
+			}
+			number = number * 10 + int(r - '0')
+		} else {
+			return nil, &InvalidRuneError{Position: i, Rune: r}
+		}
+	}
+
+	if number >= 0 {
+		numbers = append(numbers, number)
+	}
+
+	return numbers, nil
+}
+
+func nihilistNumbers(text, alphabet, header []rune) []int {
+	coords := encryptPolybius(text, alphabet, header)
+	numbers := make([]int, len(text))
+
+	for i := range numbers {
+		numbers[i] = int(coords[i * 2] - '0') * 10 + int(coords[i * 2 + 1] - '0')
+	}
+
+	return numbers
+}
+
+func encryptNihilist(text, alphabet, header, key []rune) ([]rune, error) {
+	numbers := nihilistNumbers(text, alphabet, header)
+	knumbers := nihilistNumbers(key, alphabet, header)
+
+	for i := range numbers {
+		numbers[i] += knumbers[i % len(knumbers)]
+	}
+
+	return FormatNumbers(numbers), nil
+}
+
+func decryptNihilist(text, alphabet, header, key []rune) ([]rune, error) {
+	numbers, err := ParseNumbers(text)
+	if err != nil {
+		return nil, err
+	}
+
+	knumbers := nihilistNumbers(key, alphabet, header)
+	hmap := buildIndexMap(header)
+	result := make([]rune, len(numbers))
+
+	for i, n := range numbers {
+		p := n - knumbers[i % len(knumbers)]
+		row, foundRow := hmap['0' + rune(p / 10)]
+		col, foundCol := hmap['0' + rune(p % 10)]
+
+		if p < 0 || p > 99 || !foundRow || !foundCol || row * len(header) + col >= len(alphabet) {
+			return nil, &InvalidNumberError{Position: i, Number: n}
+		}
+
+		result[i] = alphabet[row * len(header) + col]
+	}
+
+	return result, nil
+}
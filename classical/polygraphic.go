@@ -2,14 +2,21 @@ package classical
 
 import (
 	"cryptochev/utils"
+	"fmt"
 	"math"
-	"math/rand"
 
 	"gonum.org/v1/gonum/mat"
 )
 
 func NewKeyPlayfair(alphabet []rune, null rune) *KeyPlayfair { return &KeyPlayfair{Alphabet: alphabet, Null: null} }
 func NewPlayfair(text []rune, key *KeyPlayfair) *Playfair { return &Playfair{Cipher: &CipherClassical[KeyPlayfair]{Text: text, Key: key}} }
+func EncryptPlayfair(key *KeyPlayfair, text []rune) ([]rune, error) { return cryptPure(verifyPlayfair, checkPlayfair, purePlayfair, key, text, true) }
+func DecryptPlayfair(key *KeyPlayfair, text []rune) ([]rune, error) { return cryptPure(verifyPlayfair, checkPlayfair, purePlayfair, key, text, false) }
+
+func purePlayfair(k *KeyPlayfair, text []rune, encrypt bool, random *Random) ([]rune, error) {
+	crypted, _ := cryptPlayfair(text, k.Alphabet, k.Null, encrypt, random)
+	return crypted, nil
+}
 
 type KeyPlayfair struct {
 	Alphabet []rune
@@ -19,35 +26,60 @@ type KeyPlayfair struct {
 type Playfair struct { Cipher *CipherClassical[KeyPlayfair] }
 func (c *Playfair) GetText() []rune { return c.Cipher.Text }
 func (c *Playfair) GetErrors() []error { return c.Cipher.Errors }
-func (c *Playfair) Encrypt() { c.Cipher.Text = cryptPlayfair(c.Cipher.Text, c.Cipher.Key.Alphabet, c.Cipher.Key.Null, true) }
-func (c *Playfair) Decrypt() { c.Cipher.Text = cryptPlayfair(c.Cipher.Text, c.Cipher.Key.Alphabet, c.Cipher.Key.Null, false) }
-func (c *Playfair) Verify() bool { return true }
+func (c *Playfair) SetText(text []rune) { c.Cipher.Text = text }
+func (c *Playfair) Encrypt() {
+	c.Cipher.applyMapped(c.Cipher.Key.Alphabet, func(text []rune) ([]rune, []int) { return cryptPlayfair(text, c.Cipher.Key.Alphabet, c.Cipher.Key.Null, true, c.Cipher.Random) })
+}
+func (c *Playfair) Decrypt() {
+	c.Cipher.applyMapped(c.Cipher.Key.Alphabet, func(text []rune) ([]rune, []int) { return cryptPlayfair(text, c.Cipher.Key.Alphabet, c.Cipher.Key.Null, false, c.Cipher.Random) })
+}
+func (c *Playfair) EncryptE() error { return c.Cipher.cryptE(verifyPlayfair, checkPlayfair, true, c.Encrypt) }
+func (c *Playfair) DecryptE() error { return c.Cipher.cryptE(verifyPlayfair, checkPlayfair, false, c.Decrypt) }
+func (c *Playfair) Verify() bool { return c.Cipher.verify(verifyPlayfair) }
+
+func verifyPlayfair(k *KeyPlayfair) []error {
+	errs := collectErrors(
+		verifyAlphabet("alphabet", k.Alphabet),
+		verifySquare("alphabet", k.Alphabet),
+	)
+
+	if k.Null != 0 && utils.IndexOf(k.Alphabet, k.Null) == -1 {
+		errs = append(errs, keyError("null %q is not in the alphabet", k.Null))
+	}
+
+	return errs
+}
+
+func checkPlayfair(k *KeyPlayfair, text []rune, encrypt bool) error { return checkAlphabet(text, k.Alphabet) }
 
-func cryptPlayfair(text, alphabet []rune, null rune, encrypt bool) []rune {
+// Nulls split doubled runes and complete the last pair, so the index in the result of each rune of
+// the text is returned along with it.
+func cryptPlayfair(text, alphabet []rune, null rune, encrypt bool, random *Random) ([]rune, []int) {
 	result := make([]rune, 0, len(text) + len(text) / 2 + 1)
+	positions := make([]int, len(text))
 	width := int(math.Sqrt(float64(len(alphabet))))
 	amap := buildIndexMap(alphabet)
 	inc, _ := utils.SwapIf(-1, 1, encrypt)
 
 	for i := 0; i < len(text); i += 2 {
+		positions[i] = len(result)
 		i1 := amap[text[i]]
 		var i2 int
 		
 		if i != len(text) - 1 && i1 != amap[text[i + 1]] {
 			i2 = amap[text[i + 1]]
+			positions[i + 1] = len(result) + 1
 		} else {
-			if i < len(text) - 2 {
-				i--
-			}
+			i--
 			
 			if null == 0 {
-				i2 = amap[alphabet[rand.Intn(len(alphabet))]]
+				i2 = amap[random.RuneFrom(alphabet)]
 			} else {
 				i2 = amap[null]
 			}
 
 			for i2 == i1 {
-				i2 = amap[alphabet[rand.Intn(len(alphabet))]]
+				i2 = amap[random.RuneFrom(alphabet)]
 			}
 		}
 
@@ -66,13 +98,23 @@ func cryptPlayfair(text, alphabet []rune, null rune, encrypt bool) []rune {
 		}
 	}
 
-	return result
+	return result, positions
 }
 
 func NewKeyTwoSquareV(alphabet1, alphabet2 []rune, transparent bool) *KeyTwoSquareV { 
 	return &KeyTwoSquareV{Alphabet1: alphabet1, Alphabet2: alphabet2, Transparent: transparent} 
 }
 func NewTwoSquareV(text []rune, key *KeyTwoSquareV) *TwoSquareV { return &TwoSquareV{Cipher: &CipherClassical[KeyTwoSquareV]{Text: text, Key: key}} }
+func EncryptTwoSquareV(key *KeyTwoSquareV, text []rune) ([]rune, error) { return cryptPure(verifyTwoSquareV, checkTwoSquareV, pureTwoSquareV, key, text, true) }
+func DecryptTwoSquareV(key *KeyTwoSquareV, text []rune) ([]rune, error) { return cryptPure(verifyTwoSquareV, checkTwoSquareV, pureTwoSquareV, key, text, false) }
+
+func pureTwoSquareV(k *KeyTwoSquareV, text []rune, encrypt bool, _ *Random) ([]rune, error) {
+	if err := checkTwoSquareV(k, text, encrypt); err != nil {
+		return nil, err
+	}
+
+	return cryptTwoSquareV(text, k.Alphabet1, k.Alphabet2, k.Transparent, encrypt), nil
+}
 
 type KeyTwoSquareV struct {
 	Alphabet1 []rune
@@ -83,13 +125,16 @@ type KeyTwoSquareV struct {
 type TwoSquareV struct { Cipher *CipherClassical[KeyTwoSquareV] }
 func (c *TwoSquareV) GetText() []rune { return c.Cipher.Text }
 func (c *TwoSquareV) GetErrors() []error { return c.Cipher.Errors }
-func (c *TwoSquareV) Encrypt() { 
-	c.Cipher.Text = cryptTwoSquareV(c.Cipher.Text, c.Cipher.Key.Alphabet1, c.Cipher.Key.Alphabet2, c.Cipher.Key.Transparent, true) 
-}
-func (c *TwoSquareV) Decrypt() { 
-	c.Cipher.Text = cryptTwoSquareV(c.Cipher.Text, c.Cipher.Key.Alphabet1, c.Cipher.Key.Alphabet2, c.Cipher.Key.Transparent, false) 
-}
-func (c *TwoSquareV) Verify() bool { return true }
+func (c *TwoSquareV) SetText(text []rune) { c.Cipher.Text = text }
+func (c *TwoSquareV) Encrypt() { c.Cipher.crypt(pureTwoSquareV, true) }
+func (c *TwoSquareV) Decrypt() { c.Cipher.crypt(pureTwoSquareV, false) }
+func (c *TwoSquareV) EncryptE() error { return c.Cipher.cryptE(verifyTwoSquareV, checkTwoSquareV, true, c.Encrypt) }
+func (c *TwoSquareV) DecryptE() error { return c.Cipher.cryptE(verifyTwoSquareV, checkTwoSquareV, false, c.Decrypt) }
+func (c *TwoSquareV) Verify() bool { return c.Cipher.verify(verifyTwoSquareV) }
+
+func verifyTwoSquareV(k *KeyTwoSquareV) []error { return verifySquares(k.Alphabet1, k.Alphabet2) }
+
+func checkTwoSquareV(k *KeyTwoSquareV, text []rune, encrypt bool) error { return checkPairs(text, k.Alphabet1, k.Alphabet2) }
 
 func cryptTwoSquareV(text, alphabet1, alphabet2 []rune, transparent, encrypt bool) []rune {
 	result := make([]rune, len(text))
@@ -125,6 +170,20 @@ func NewKeyTwoSquareH(alphabet1, alphabet2 []rune, transparent bool) *KeyTwoSqua
 	return &KeyTwoSquareH{Alphabet1: alphabet1, Alphabet2: alphabet2, Transparent: transparent} 
 }
 func NewTwoSquareH(text []rune, key *KeyTwoSquareH) *TwoSquareH { return &TwoSquareH{Cipher: &CipherClassical[KeyTwoSquareH]{Text: text, Key: key}} }
+func EncryptTwoSquareH(key *KeyTwoSquareH, text []rune) ([]rune, error) { return cryptPure(verifyTwoSquareH, checkTwoSquareH, pureTwoSquareH, key, text, true) }
+func DecryptTwoSquareH(key *KeyTwoSquareH, text []rune) ([]rune, error) { return cryptPure(verifyTwoSquareH, checkTwoSquareH, pureTwoSquareH, key, text, false) }
+
+func pureTwoSquareH(k *KeyTwoSquareH, text []rune, encrypt bool, _ *Random) ([]rune, error) {
+	if err := checkTwoSquareH(k, text, encrypt); err != nil {
+		return nil, err
+	}
+
+	if encrypt {
+		return encryptTwoSquareH(text, k.Alphabet1, k.Alphabet2, k.Transparent), nil
+	}
+
+	return decryptTwoSquareH(text, k.Alphabet1, k.Alphabet2, k.Transparent), nil
+}
 
 type KeyTwoSquareH struct {
 	Alphabet1 []rune
@@ -135,13 +194,37 @@ type KeyTwoSquareH struct {
 type TwoSquareH struct { Cipher *CipherClassical[KeyTwoSquareH] }
 func (c *TwoSquareH) GetText() []rune { return c.Cipher.Text }
 func (c *TwoSquareH) GetErrors() []error { return c.Cipher.Errors }
-func (c *TwoSquareH) Encrypt() { 
-	c.Cipher.Text = encryptTwoSquareH(c.Cipher.Text, c.Cipher.Key.Alphabet1, c.Cipher.Key.Alphabet2, c.Cipher.Key.Transparent) 
+func (c *TwoSquareH) SetText(text []rune) { c.Cipher.Text = text }
+func (c *TwoSquareH) Encrypt() { c.Cipher.crypt(pureTwoSquareH, true) }
+func (c *TwoSquareH) Decrypt() { c.Cipher.crypt(pureTwoSquareH, false) }
+func (c *TwoSquareH) EncryptE() error { return c.Cipher.cryptE(verifyTwoSquareH, checkTwoSquareH, true, c.Encrypt) }
+func (c *TwoSquareH) DecryptE() error { return c.Cipher.cryptE(verifyTwoSquareH, checkTwoSquareH, false, c.Decrypt) }
+func (c *TwoSquareH) Verify() bool { return c.Cipher.verify(verifyTwoSquareH) }
+
+func verifyTwoSquareH(k *KeyTwoSquareH) []error { return verifySquares(k.Alphabet1, k.Alphabet2) }
+
+func checkTwoSquareH(k *KeyTwoSquareH, text []rune, encrypt bool) error {
+	if encrypt {
+		return checkPairs(text, k.Alphabet1, k.Alphabet2)
+	}
+
+	return checkPairs(text, k.Alphabet2, k.Alphabet1)
 }
-func (c *TwoSquareH) Decrypt() { 
-	c.Cipher.Text = decryptTwoSquareH(c.Cipher.Text, c.Cipher.Key.Alphabet1, c.Cipher.Key.Alphabet2, c.Cipher.Key.Transparent) 
+
+func verifySquares(alphabets ...[]rune) []error {
+	errs := make([]error, 0)
+
+	for i, a := range alphabets {
+		name := fmt.Sprintf("alphabet %d", i + 1)
+		errs = append(errs, collectErrors(
+			verifyAlphabet(name, a),
+			verifySquare(name, a),
+			verifySameLength("alphabet 1", alphabets[0], name, a),
+		)...)
+	}
+
+	return errs
 }
-func (c *TwoSquareH) Verify() bool { return true }
 
 func encryptTwoSquareH(text, alphabet1, alphabet2 []rune, transparent bool) []rune {
 	result := make([]rune, len(text))
@@ -205,6 +288,20 @@ func NewKeyFourSquare(a1, a2, a3, a4 []rune) *KeyFourSquare {
 	return &KeyFourSquare{Alphabet1: a1, Alphabet2: a2, Alphabet3: a3, Alphabet4: a4}
 }
 func NewFourSquare(text []rune, key *KeyFourSquare) *FourSquare { return &FourSquare{Cipher: &CipherClassical[KeyFourSquare]{Text: text, Key: key}} }
+func EncryptFourSquare(key *KeyFourSquare, text []rune) ([]rune, error) { return cryptPure(verifyFourSquare, checkFourSquare, pureFourSquare, key, text, true) }
+func DecryptFourSquare(key *KeyFourSquare, text []rune) ([]rune, error) { return cryptPure(verifyFourSquare, checkFourSquare, pureFourSquare, key, text, false) }
+
+func pureFourSquare(k *KeyFourSquare, text []rune, encrypt bool, _ *Random) ([]rune, error) {
+	if err := checkFourSquare(k, text, encrypt); err != nil {
+		return nil, err
+	}
+
+	if encrypt {
+		return encryptFourSquare(text, k.Alphabet1, k.Alphabet2, k.Alphabet3, k.Alphabet4), nil
+	}
+
+	return decryptFourSquare(text, k.Alphabet1, k.Alphabet2, k.Alphabet3, k.Alphabet4), nil
+}
 
 type KeyFourSquare struct {
 	Alphabet1 []rune
@@ -216,15 +313,22 @@ type KeyFourSquare struct {
 type FourSquare struct { Cipher *CipherClassical[KeyFourSquare] }
 func (c *FourSquare) GetText() []rune { return c.Cipher.Text }
 func (c *FourSquare) GetErrors() []error { return c.Cipher.Errors }
-func (c *FourSquare) Encrypt() { 
-	c.Cipher.Text = encryptFourSquare(c.Cipher.Text, c.Cipher.Key.Alphabet1, c.Cipher.Key.Alphabet2, 
-									  c.Cipher.Key.Alphabet3, c.Cipher.Key.Alphabet4)
-}
-func (c *FourSquare) Decrypt() { 
-	c.Cipher.Text = decryptFourSquare(c.Cipher.Text, c.Cipher.Key.Alphabet1, c.Cipher.Key.Alphabet2, 
-									  c.Cipher.Key.Alphabet3, c.Cipher.Key.Alphabet4)
+func (c *FourSquare) SetText(text []rune) { c.Cipher.Text = text }
+func (c *FourSquare) Encrypt() { c.Cipher.crypt(pureFourSquare, true) }
+func (c *FourSquare) Decrypt() { c.Cipher.crypt(pureFourSquare, false) }
+func (c *FourSquare) EncryptE() error { return c.Cipher.cryptE(verifyFourSquare, checkFourSquare, true, c.Encrypt) }
+func (c *FourSquare) DecryptE() error { return c.Cipher.cryptE(verifyFourSquare, checkFourSquare, false, c.Decrypt) }
+func (c *FourSquare) Verify() bool { return c.Cipher.verify(verifyFourSquare) }
+
+func verifyFourSquare(k *KeyFourSquare) []error { return verifySquares(k.Alphabet1, k.Alphabet2, k.Alphabet3, k.Alphabet4) }
+
+func checkFourSquare(k *KeyFourSquare, text []rune, encrypt bool) error {
+	if encrypt {
+		return checkPairs(text, k.Alphabet1, k.Alphabet4)
+	}
+
+	return checkPairs(text, k.Alphabet2, k.Alphabet3)
 }
-func (c *FourSquare) Verify() bool { return true }
 
 func encryptFourSquare(text, a1, a2, a3, a4 []rune) []rune {
 	result := make([]rune, len(text))
@@ -264,6 +368,10 @@ func decryptFourSquare(text, a1, a2, a3, a4 []rune) []rune {
 
 func NewKeyHill(alphabet []rune, m *mat.Dense) *KeyHill { return &KeyHill{Alphabet: alphabet, Matrix: m} }
 func NewHill(text []rune, key *KeyHill) *Hill { return &Hill{Cipher: &CipherClassical[KeyHill]{Text: text, Key: key}} }
+func EncryptHill(key *KeyHill, text []rune) ([]rune, error) { return cryptPure(verifyHill, checkHill, pureHill, key, text, true) }
+func DecryptHill(key *KeyHill, text []rune) ([]rune, error) { return cryptPure(verifyHill, checkHill, pureHill, key, text, false) }
+
+func pureHill(k *KeyHill, text []rune, encrypt bool, random *Random) ([]rune, error) { return cryptHill(text, k.Alphabet, k.Matrix, encrypt, random) }
 
 type KeyHill struct {
 	Alphabet []rune
@@ -273,21 +381,62 @@ type KeyHill struct {
 type Hill struct { Cipher *CipherClassical[KeyHill] }
 func (c *Hill) GetText() []rune { return c.Cipher.Text }
 func (c *Hill) GetErrors() []error { return c.Cipher.Errors }
-func (c *Hill) Encrypt() { c.Cipher.Text = cryptHill(c.Cipher.Text, c.Cipher.Key.Alphabet, c.Cipher.Key.Matrix, true) }
-func (c *Hill) Decrypt() { c.Cipher.Text = cryptHill(c.Cipher.Text, c.Cipher.Key.Alphabet, c.Cipher.Key.Matrix, false) }
-func (c *Hill) Verify() bool { return true }
+func (c *Hill) SetText(text []rune) { c.Cipher.Text = text }
+func (c *Hill) Encrypt() { c.Cipher.crypt(pureHill, true) }
+func (c *Hill) Decrypt() { c.Cipher.crypt(pureHill, false) }
+func (c *Hill) EncryptE() error { return c.Cipher.cryptE(verifyHill, checkHill, true, c.Encrypt) }
+func (c *Hill) DecryptE() error { return c.Cipher.cryptE(verifyHill, checkHill, false, c.Decrypt) }
+func (c *Hill) Verify() bool { return c.Cipher.verify(verifyHill) }
+
+func verifyHill(k *KeyHill) []error {
+	errs := collectErrors(verifyAlphabet("alphabet", k.Alphabet))
+
+	if k.Matrix == nil {
+		return append(errs, keyError("matrix is missing"))
+	}
 
-func cryptHill(text, alphabet []rune, m *mat.Dense, encrypt bool) []rune {
+	r, c := k.Matrix.Dims()
+	if r != c {
+		return append(errs, keyError("matrix is %dx%d, it must be square", r, c))
+	}
+
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			if v := k.Matrix.At(i, j); v != math.Trunc(v) {
+				return append(errs, keyError("matrix value %v at (%d, %d) is not an integer", v, i, j))
+			}
+		}
+	}
+
+	if len(k.Alphabet) == 0 {
+		return errs
+	}
+
+	if det := utils.ModDeterminant(k.Matrix, len(k.Alphabet)); !utils.IsCoprime(uint(det), uint(len(k.Alphabet))) {
+		errs = append(errs, keyError("matrix determinant %d is not invertible modulo %d", det, len(k.Alphabet)))
+	}
+
+	return errs
+}
+
+func checkHill(k *KeyHill, text []rune, encrypt bool) error { return checkAlphabet(text, k.Alphabet) }
+
+func cryptHill(text, alphabet []rune, m *mat.Dense, encrypt bool, random *Random) ([]rune, error) {
 	r, _ := m.Dims()
-	text = ToPadded(text, r)
-	result := make([]rune, len(text))
 	amap := buildIndexMap(alphabet)
 	col := make([]float64, r)
 
 	if !encrypt {
-		utils.ModInverseMatrix(m, len(alphabet))
+		inv, err := utils.ModInverseMatrix(m, len(alphabet))
+		if err != nil {
+			return nil, keyError("%v", err)
+		}
+		m = inv
 	}
 
+	text = random.Padded(text, r)
+	result := make([]rune, len(text))
+
 	for i := 0; i < len(text); i += r {
 		for j := range col {
 			col[j] = float64(amap[text[i + j]])
@@ -300,5 +449,5 @@ func cryptHill(text, alphabet []rune, m *mat.Dense, encrypt bool) []rune {
 		}
 	}
 
-	return result
+	return result, nil
 }
@@ -0,0 +1,58 @@
+package classical
+
+import (
+	"cryptochev/utils"
+	"math/rand"
+)
+
+// A nil Random, or one without a generator, draws from a crypto/rand backed source.
+type Random struct {
+	Rand *rand.Rand
+}
+
+func NewRandom(r *rand.Rand) *Random { return &Random{Rand: r} }
+func NewSeededRandom(seed int64) *Random { return NewRandom(utils.NewSeededRand(seed)) }
+
+var defaultRandom = NewRandom(nil)
+
+func (r *Random) rand() *rand.Rand {
+	if r == nil {
+		return utils.RandOrDefault(nil)
+	}
+
+	return utils.RandOrDefault(r.Rand)
+}
+
+func (r *Random) Intn(n int) int { return r.rand().Intn(n) }
+func (r *Random) Float64() float64 { return r.rand().Float64() }
+func (r *Random) Shuffle(rs []rune) []rune { return utils.ShuffleRand(r.rand(), rs) }
+
+func (r *Random) AlphabetL() []rune { return r.Shuffle([]rune(AlphabetL)) }
+func (r *Random) AlphabetL25() []rune { return r.Shuffle([]rune(AlphabetL25)) }
+func (r *Random) AlphabetL36() []rune { return r.Shuffle([]rune(AlphabetL36)) }
+
+func (r *Random) AlphabetKey(alphabet, key []rune) []rune {
+	ukey, remains := buildAlphabetKey(alphabet, key)
+	return append(ukey, r.Shuffle(remains)...)
+}
+
+func (r *Random) AlphabetKeyL25(key []rune) []rune { return r.AlphabetKey([]rune(AlphabetL25), key) }
+func (r *Random) AlphabetKeyL36(key []rune) []rune { return r.AlphabetKey([]rune(AlphabetL36), key) }
+
+func (r *Random) Letter() rune { return rune(65 + r.Intn(26)) }
+func (r *Random) RuneFrom(rs []rune) rune { return rs[r.Intn(len(rs))] }
+
+func (r *Random) Padded(rs []rune, width int) []rune {
+	result := make([]rune, len(rs), len(rs) + width)
+	copy(result, rs)
+
+	if len(rs) % width != 0 {
+		pad := width - len(rs) % width
+
+		for i := 0; i < pad; i++ {
+			result = append(result, r.RuneFrom(rs))
+		}
+	}
+
+	return result
+}
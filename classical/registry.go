@@ -0,0 +1,509 @@
+package classical
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+var ErrUnknownCipher = errors.New("unknown cipher")
+var ErrInvalidParam = errors.New("invalid parameter")
+
+type Category int
+const (
+	CategorySubstitution Category = iota
+	CategoryTransposition
+	CategoryPolygraphic
+	CategoryFractionating
+)
+
+func (c Category) String() string {
+	switch c {
+	case CategorySubstitution:
+		return "substitution"
+	case CategoryTransposition:
+		return "transposition"
+	case CategoryPolygraphic:
+		return "polygraphic"
+	case CategoryFractionating:
+		return "fractionating"
+	}
+
+	return "unknown"
+}
+
+type ParamType string
+const (
+	ParamRunes ParamType = "runes"
+	ParamRune ParamType = "rune"
+	ParamString ParamType = "string"
+	ParamStrings ParamType = "strings"
+	ParamInt ParamType = "int"
+	ParamBool ParamType = "bool"
+	ParamMatrix ParamType = "matrix"
+	ParamRoute ParamType = "route"
+	ParamRotors ParamType = "rotors"
+)
+
+type ParamInfo struct {
+	Name string
+	Type ParamType
+}
+
+type Params map[string]any
+
+type CipherInfo struct {
+	Name string
+	Category Category
+	Involutive bool
+	Alphabet string
+	Params []ParamInfo
+	NewKey func(p Params) (any, error)
+	NewCipher func(text []rune, key any) (ICipherClassical, error)
+}
+
+var registry = make(map[string]*CipherInfo)
+
+func Register(info *CipherInfo) {
+	if _, found := registry[info.Name]; found {
+		panic("classical: cipher " + info.Name + " registered twice")
+	}
+
+	registry[info.Name] = info
+}
+
+func Lookup(name string) (*CipherInfo, bool) {
+	info, found := registry[name]
+	return info, found
+}
+
+func Ciphers() []*CipherInfo {
+	infos := make([]*CipherInfo, 0, len(registry))
+	for _, info := range registry {
+		infos = append(infos, info)
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Name < infos[j].Name
+	})
+
+	return infos
+}
+
+func NewCipher(name string, text []rune, p Params) (ICipherClassical, error) {
+	info, found := Lookup(name)
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownCipher, name)
+	}
+
+	key, err := info.NewKey(p)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := info.NewCipher(text, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if !c.Verify() {
+		return nil, c.GetErrors()[0]
+	}
+
+	return c, nil
+}
+
+func register[K CipherClassicalKey, C ICipherClassical](info CipherInfo, newKey func(r *paramReader) *K, newCipher func([]rune, *K) C) {
+	info.NewKey = func(p Params) (any, error) {
+		r := &paramReader{params: p}
+		key := newKey(r)
+		if r.err != nil {
+			return nil, r.err
+		}
+
+		return key, nil
+	}
+
+	info.NewCipher = func(text []rune, key any) (ICipherClassical, error) {
+		k, ok := key.(*K)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s does not accept a key of type %T", ErrInvalidKey, info.Name, key)
+		}
+
+		return newCipher(text, k), nil
+	}
+
+	Register(&info)
+}
+
+type paramReader struct {
+	params Params
+	err error
+}
+
+func (r *paramReader) fail(name string, v any, expected string) {
+	if r.err == nil {
+		r.err = fmt.Errorf("%w: %s must be %s, got %T", ErrInvalidParam, name, expected, v)
+	}
+}
+
+func (r *paramReader) runes(name string, def string) []rune {
+	v, found := r.params[name]
+	if !found {
+		return []rune(def)
+	}
+
+	switch v := v.(type) {
+	case string:
+		return []rune(v)
+	case []rune:
+		return v
+	}
+
+	r.fail(name, v, "a string")
+	return nil
+}
+
+func (r *paramReader) rune(name string, def rune) rune {
+	v, found := r.params[name]
+	if !found {
+		return def
+	}
+
+	switch v := v.(type) {
+	case rune:
+		return v
+	case string:
+		if rs := []rune(v); len(rs) <= 1 {
+			if len(rs) == 0 {
+				return 0
+			}
+			return rs[0]
+		}
+	}
+
+	r.fail(name, v, "a single rune")
+	return def
+}
+
+func (r *paramReader) string(name string, def string) string {
+	v, found := r.params[name]
+	if !found {
+		return def
+	}
+
+	switch v := v.(type) {
+	case string:
+		return v
+	case []rune:
+		return string(v)
+	}
+
+	r.fail(name, v, "a string")
+	return def
+}
+
+// A single string is split on spaces.
+func (r *paramReader) strings(name string, def string) []string {
+	v, found := r.params[name]
+	if !found {
+		return strings.Fields(def)
+	}
+
+	switch v := v.(type) {
+	case []string:
+		return v
+	case string:
+		return strings.Fields(v)
+	case []any:
+		result := make([]string, len(v))
+		for i, e := range v {
+			s, ok := e.(string)
+			if !ok {
+				r.fail(name, v, "a list of strings")
+				return nil
+			}
+			result[i] = s
+		}
+		return result
+	}
+
+	r.fail(name, v, "a list of strings")
+	return nil
+}
+
+func (r *paramReader) int(name string, def int) int {
+	v, found := r.params[name]
+	if !found {
+		return def
+	}
+
+	switch v := v.(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		if v == math.Trunc(v) {
+			return int(v)
+		}
+	case string:
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	}
+
+	r.fail(name, v, "an integer")
+	return def
+}
+
+func (r *paramReader) bool(name string, def bool) bool {
+	v, found := r.params[name]
+	if !found {
+		return def
+	}
+
+	switch v := v.(type) {
+	case bool:
+		return v
+	case string:
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+
+	r.fail(name, v, "a boolean")
+	return def
+}
+
+func (r *paramReader) matrix(name string) *mat.Dense {
+	v, found := r.params[name]
+	if !found {
+		return nil
+	}
+
+	switch v := v.(type) {
+	case *mat.Dense:
+		return v
+	case [][]float64:
+		return denseFromRows(v)
+	case [][]int:
+		rows := make([][]float64, len(v))
+		for i, row := range v {
+			rows[i] = make([]float64, len(row))
+			for j, x := range row {
+				rows[i][j] = float64(x)
+			}
+		}
+		return denseFromRows(rows)
+	case []any:
+		rows := make([][]float64, len(v))
+		for i, row := range v {
+			cols, ok := row.([]any)
+			if !ok {
+				r.fail(name, v, "a list of rows")
+				return nil
+			}
+
+			rows[i] = make([]float64, len(cols))
+			for j, x := range cols {
+				f, ok := x.(float64)
+				if !ok {
+					r.fail(name, v, "a list of numeric rows")
+					return nil
+				}
+				rows[i][j] = f
+			}
+		}
+		return denseFromRows(rows)
+	}
+
+	r.fail(name, v, "a matrix")
+	return nil
+}
+
+func (r *paramReader) route(name string, def route) route {
+	v, found := r.params[name]
+	if !found {
+		return def
+	}
+
+	switch v := v.(type) {
+	case route:
+		return v
+	case string:
+		if rt, found := routeNames[v]; found {
+			return rt
+		}
+	}
+
+	r.fail(name, v, "a route name")
+	return def
+}
+
+func (r *paramReader) rotors(name string) []KeyRotor {
+	v, found := r.params[name]
+	if !found {
+		return nil
+	}
+
+	if v, ok := v.([]KeyRotor); ok {
+		return v
+	}
+
+	r.fail(name, v, "a list of rotors")
+	return nil
+}
+
+func denseFromRows(rows [][]float64) *mat.Dense {
+	if len(rows) == 0 || len(rows[0]) == 0 {
+		return nil
+	}
+
+	data := make([]float64, 0, len(rows) * len(rows[0]))
+	for _, row := range rows {
+		if len(row) != len(rows[0]) {
+			return nil
+		}
+		data = append(data, row...)
+	}
+
+	return mat.NewDense(len(rows), len(rows[0]), data)
+}
+
+func init() {
+	alphabet := ParamInfo{"alphabet", ParamRunes}
+	key := ParamInfo{"key", ParamRunes}
+	none := func(r *paramReader) *KeyNone { return &KeyNone{} }
+
+	register(CipherInfo{Name: "substitute", Category: CategorySubstitution, Alphabet: AlphabetL, Params: []ParamInfo{alphabet, {"sAlphabet", ParamRunes}}},
+		func(r *paramReader) *KeySubstitute { return NewKeySubstitute(r.runes("alphabet", AlphabetL), r.runes("sAlphabet", "")) }, NewSubstitute)
+	register(CipherInfo{Name: "shift", Category: CategorySubstitution, Params: []ParamInfo{{"shift", ParamInt}}},
+		func(r *paramReader) *KeyShift { return NewKeyShift(r.int("shift", 0)) }, NewShift)
+	register(CipherInfo{Name: "shift-alphabet", Category: CategorySubstitution, Alphabet: AlphabetL, Params: []ParamInfo{alphabet, {"shift", ParamInt}}},
+		func(r *paramReader) *KeyShiftAlphabet { return NewKeyShiftAlphabet(r.runes("alphabet", AlphabetL), r.int("shift", 0)) }, NewShiftAlphabet)
+	register(CipherInfo{Name: "caesar", Category: CategorySubstitution, Alphabet: AlphabetL, Params: []ParamInfo{{"shift", ParamInt}}},
+		func(r *paramReader) *KeyCaesar { return NewKeyCaesar(r.int("shift", 3)) }, NewCaesar)
+	register(CipherInfo{Name: "rot13", Category: CategorySubstitution, Involutive: true, Alphabet: AlphabetL},
+		none, func(text []rune, _ *KeyNone) *ROT13 { return NewROT13(text) })
+	register(CipherInfo{Name: "affine", Category: CategorySubstitution, Alphabet: AlphabetL, Params: []ParamInfo{alphabet, {"a", ParamInt}, {"b", ParamInt}}},
+		func(r *paramReader) *KeyAffine { return NewKeyAffine(r.runes("alphabet", AlphabetL), r.int("a", 1), r.int("b", 0)) }, NewAffine)
+	register(CipherInfo{Name: "atbash", Category: CategorySubstitution, Involutive: true, Alphabet: AlphabetL, Params: []ParamInfo{alphabet}},
+		func(r *paramReader) *KeyAtbash { return NewKeyAtbash(r.runes("alphabet", AlphabetL)) }, NewAtbash)
+	register(CipherInfo{Name: "chaocipher", Category: CategorySubstitution, Alphabet: AlphabetL, Params: []ParamInfo{{"left", ParamRunes}, {"right", ParamRunes}}},
+		func(r *paramReader) *KeyChaocipher { return NewKeyChaocipher(r.runes("left", ""), r.runes("right", "")) }, NewChaocipher)
+	register(CipherInfo{Name: "vigenere", Category: CategorySubstitution, Alphabet: AlphabetL, Params: []ParamInfo{alphabet, key}},
+		func(r *paramReader) *KeyVigenere { return NewKeyVigenere(r.runes("alphabet", AlphabetL), r.runes("key", "")) }, NewVigenere)
+	register(CipherInfo{Name: "vigenere-beaufort", Category: CategorySubstitution, Alphabet: AlphabetL, Params: []ParamInfo{alphabet, key}},
+		func(r *paramReader) *KeyVigenere { return NewKeyVigenere(r.runes("alphabet", AlphabetL), r.runes("key", "")) }, NewVigenereBeaufort)
+	register(CipherInfo{Name: "vigenere-gronsfeld", Category: CategorySubstitution, Alphabet: AlphabetL, Params: []ParamInfo{alphabet, key}},
+		func(r *paramReader) *KeyVigenere { return NewKeyVigenere(r.runes("alphabet", AlphabetL), r.runes("key", "")) }, NewVigenereGronsfeld)
+	register(CipherInfo{Name: "autokey", Category: CategorySubstitution, Alphabet: AlphabetL, Params: []ParamInfo{alphabet, {"primer", ParamRunes}}},
+		func(r *paramReader) *KeyAutokey { return NewKeyAutokey(r.runes("alphabet", AlphabetL), r.runes("primer", "")) }, NewAutokey)
+	register(CipherInfo{Name: "beaufort", Category: CategorySubstitution, Involutive: true, Alphabet: AlphabetL, Params: []ParamInfo{alphabet, key}},
+		func(r *paramReader) *KeyBeaufort { return NewKeyBeaufort(r.runes("alphabet", AlphabetL), r.runes("key", "")) }, NewBeaufort)
+	register(CipherInfo{Name: "solitaire", Category: CategorySubstitution, Alphabet: AlphabetL, Params: []ParamInfo{{"deck", ParamStrings}, {"passphrase", ParamRunes}}},
+		func(r *paramReader) *KeySolitaire {
+			deck, err := ParseSolitaireDeck(r.strings("deck", ""))
+			if err != nil && r.err == nil {
+				r.err = err
+			}
+			return NewKeySolitaire(deck, r.runes("passphrase", ""))
+		}, NewSolitaire)
+	register(CipherInfo{Name: "enigma", Category: CategorySubstitution, Involutive: true, Alphabet: AlphabetL,
+		Params: []ParamInfo{{"reflector", ParamString}, {"rotors", ParamStrings}, {"rings", ParamRunes}, {"positions", ParamRunes}, {"plugboard", ParamRunes}}},
+		func(r *paramReader) *KeyEnigma {
+			return NewKeyEnigma(r.string("reflector", "B"), r.strings("rotors", "I II III"), r.runes("rings", "AAA"), r.runes("positions", "AAA"), r.runes("plugboard", ""))
+		}, NewEnigma)
+	register(CipherInfo{Name: "rotor-machine", Category: CategorySubstitution, Alphabet: AlphabetL,
+		Params: []ParamInfo{alphabet, {"entry", ParamRunes}, {"rotors", ParamRotors}, {"reflector", ParamRunes}, {"stepping", ParamString}, {"control", ParamRotors}, {"index", ParamRotors}}},
+		func(r *paramReader) *KeyRotorMachine {
+			k := NewKeyRotorMachine(r.runes("alphabet", AlphabetL), r.runes("entry", ""), r.rotors("rotors"), r.runes("reflector", ""), RotorStepping(r.string("stepping", string(SteppingOdometer))))
+			k.Control, k.Index = r.rotors("control"), r.rotors("index")
+			return k
+		}, NewRotorMachine)
+	register(CipherInfo{Name: "m209", Category: CategorySubstitution, Alphabet: AlphabetL, Params: []ParamInfo{{"pins", ParamStrings}, {"lugs", ParamStrings}, {"positions", ParamRunes}}},
+		func(r *paramReader) *KeyM209 {
+			lugs, err := ParseM209Lugs(r.strings("lugs", ""))
+			if err != nil && r.err == nil {
+				r.err = err
+			}
+			return NewKeyM209(ParseM209Pins(r.strings("pins", "")), lugs, r.runes("positions", "AAAAAA"))
+		}, NewM209)
+	register(CipherInfo{Name: "sigaba", Category: CategorySubstitution, Alphabet: AlphabetL,
+		Params: []ParamInfo{{"cipher", ParamStrings}, {"control", ParamStrings}, {"index", ParamStrings},
+			{"cipherPositions", ParamRunes}, {"controlPositions", ParamRunes}, {"indexPositions", ParamRunes}}},
+		func(r *paramReader) *KeySIGABA {
+			return NewKeySIGABA(r.strings("cipher", "0 1 2 3 4"), r.strings("control", "5 6 7 8 9"), r.strings("index", "0 1 2 3 4"),
+				r.runes("cipherPositions", "AAAAA"), r.runes("controlPositions", "AAAAA"), r.runes("indexPositions", "00000"))
+		}, NewSIGABA)
+
+	register(CipherInfo{Name: "polybius", Category: CategorySubstitution, Alphabet: AlphabetL25, Params: []ParamInfo{alphabet, {"header", ParamRunes}}},
+		func(r *paramReader) *KeyPolybius { return NewKeyPolybius(r.runes("alphabet", AlphabetL25), r.runes("header", "12345")) }, NewPolybius)
+	register(CipherInfo{Name: "nihilist", Category: CategorySubstitution, Alphabet: AlphabetL25, Params: []ParamInfo{alphabet, {"header", ParamRunes}, key}},
+		func(r *paramReader) *KeyNihilist { return NewKeyNihilist(r.runes("alphabet", AlphabetL25), r.runes("header", "12345"), r.runes("key", "")) }, NewNihilist)
+	register(CipherInfo{Name: "adfgx", Category: CategoryFractionating, Alphabet: AlphabetL25, Params: []ParamInfo{alphabet, key}},
+		func(r *paramReader) *KeyADFGX { return NewKeyADFGX(r.runes("alphabet", AlphabetL25), r.runes("key", "")) }, NewADFGX)
+	register(CipherInfo{Name: "bifid", Category: CategoryFractionating, Alphabet: AlphabetL25, Params: []ParamInfo{alphabet, {"period", ParamInt}}},
+		func(r *paramReader) *KeyBifid { return NewKeyBifid(r.runes("alphabet", AlphabetL25), r.int("period", 5)) }, NewBifid)
+	register(CipherInfo{Name: "trifid", Category: CategoryFractionating, Alphabet: AlphabetL27, Params: []ParamInfo{alphabet, {"period", ParamInt}}},
+		func(r *paramReader) *KeyTrifid { return NewKeyTrifid(r.runes("alphabet", AlphabetL27), r.int("period", 5)) }, NewTrifid)
+	register(CipherInfo{Name: "adfgvx", Category: CategoryFractionating, Alphabet: AlphabetL36, Params: []ParamInfo{alphabet, key}},
+		func(r *paramReader) *KeyADFGVX { return NewKeyADFGVX(r.runes("alphabet", AlphabetL36), r.runes("key", "")) }, NewADFGVX)
+	register(CipherInfo{Name: "checkerboard", Category: CategoryFractionating, Alphabet: CheckerboardDefault, Params: []ParamInfo{alphabet, {"header", ParamRunes}, {"escape", ParamRune}}},
+		func(r *paramReader) *KeyCheckerboard { return NewKeyCheckerboard(r.runes("alphabet", CheckerboardDefault), r.runes("header", "0123456789"), r.rune("escape", '/')) }, NewCheckerboard)
+	register(CipherInfo{Name: "vic", Category: CategoryFractionating, Alphabet: CheckerboardDefault,
+		Params: []ParamInfo{alphabet, {"phrase", ParamRunes}, {"date", ParamRunes}, {"personal", ParamInt}, {"indicator", ParamRunes}, {"escape", ParamRune}}},
+		func(r *paramReader) *KeyVIC {
+			return NewKeyVIC(r.runes("phrase", ""), r.runes("date", ""), r.int("personal", 0), r.runes("indicator", ""), r.runes("alphabet", CheckerboardDefault), r.rune("escape", '/'))
+		}, NewVIC)
+
+	register(CipherInfo{Name: "column", Category: CategoryTransposition, Params: []ParamInfo{key}},
+		func(r *paramReader) *KeyColumn { return NewKeyColumn(r.runes("key", "")) }, NewColumn)
+	register(CipherInfo{Name: "myszkowski", Category: CategoryTransposition, Params: []ParamInfo{key}},
+		func(r *paramReader) *KeyMyszkowski { return NewKeyMyszkowski(r.runes("key", "")) }, NewMyszkowski)
+	register(CipherInfo{Name: "column-dcount", Category: CategoryTransposition, Params: []ParamInfo{key, {"dKey", ParamRunes}}},
+		func(r *paramReader) *KeyColumnDCount { return NewKeyColumnDCount(r.runes("key", ""), r.runes("dKey", "")) }, NewColumnDCount)
+	register(CipherInfo{Name: "column-dline", Category: CategoryTransposition, Params: []ParamInfo{key, {"fill", ParamBool}}},
+		func(r *paramReader) *KeyColumnDLine { return NewKeyColumnDLine(r.runes("key", ""), r.bool("fill", false)) }, NewColumnDLine)
+	register(CipherInfo{Name: "nihilist-transposition", Category: CategoryTransposition, Params: []ParamInfo{key, {"byColumns", ParamBool}}},
+		func(r *paramReader) *KeyNihilistTransposition { return NewKeyNihilistTransposition(r.runes("key", ""), r.bool("byColumns", false)) }, NewNihilistTransposition)
+	register(CipherInfo{Name: "reverse", Category: CategoryTransposition, Involutive: true},
+		none, func(text []rune, _ *KeyNone) *Reverse { return NewReverse(text) })
+	register(CipherInfo{Name: "zigzag", Category: CategoryTransposition, Params: []ParamInfo{{"lines", ParamInt}}},
+		func(r *paramReader) *KeyZigzag { return NewKeyZigzag(r.int("lines", 3)) }, NewZigzag)
+	register(CipherInfo{Name: "scytale", Category: CategoryTransposition, Params: []ParamInfo{{"lines", ParamInt}}},
+		func(r *paramReader) *KeyScytale { return NewKeyScytale(r.int("lines", 3)) }, NewScytale)
+	register(CipherInfo{Name: "route-spiral", Category: CategoryTransposition, Params: []ParamInfo{{"width", ParamInt}, {"route", ParamRoute}}},
+		func(r *paramReader) *KeyRoute { return NewKeyRoute(r.int("width", 0), r.route("route", ROUTE_TLR)) }, NewRouteSpiral)
+	register(CipherInfo{Name: "route-serpent", Category: CategoryTransposition, Params: []ParamInfo{{"width", ParamInt}, {"route", ParamRoute}}},
+		func(r *paramReader) *KeyRoute { return NewKeyRoute(r.int("width", 0), r.route("route", ROUTE_TLR)) }, NewRouteSerpent)
+	register(CipherInfo{Name: "magnet", Category: CategoryTransposition},
+		none, func(text []rune, _ *KeyNone) *Magnet { return NewMagnet(text) })
+	register(CipherInfo{Name: "elastic", Category: CategoryTransposition},
+		none, func(text []rune, _ *KeyNone) *Elastic { return NewElastic(text) })
+
+	register(CipherInfo{Name: "playfair", Category: CategoryPolygraphic, Alphabet: AlphabetL25, Params: []ParamInfo{alphabet, {"null", ParamRune}}},
+		func(r *paramReader) *KeyPlayfair { return NewKeyPlayfair(r.runes("alphabet", AlphabetL25), r.rune("null", 0)) }, NewPlayfair)
+	square := []ParamInfo{{"alphabet1", ParamRunes}, {"alphabet2", ParamRunes}, {"transparent", ParamBool}}
+	register(CipherInfo{Name: "two-square-v", Category: CategoryPolygraphic, Alphabet: AlphabetL25, Params: square},
+		func(r *paramReader) *KeyTwoSquareV {
+			return NewKeyTwoSquareV(r.runes("alphabet1", AlphabetL25), r.runes("alphabet2", AlphabetL25), r.bool("transparent", false))
+		}, NewTwoSquareV)
+	register(CipherInfo{Name: "two-square-h", Category: CategoryPolygraphic, Alphabet: AlphabetL25, Params: square},
+		func(r *paramReader) *KeyTwoSquareH {
+			return NewKeyTwoSquareH(r.runes("alphabet1", AlphabetL25), r.runes("alphabet2", AlphabetL25), r.bool("transparent", false))
+		}, NewTwoSquareH)
+	register(CipherInfo{Name: "four-square", Category: CategoryPolygraphic, Alphabet: AlphabetL25,
+		Params: []ParamInfo{{"alphabet1", ParamRunes}, {"alphabet2", ParamRunes}, {"alphabet3", ParamRunes}, {"alphabet4", ParamRunes}}},
+		func(r *paramReader) *KeyFourSquare {
+			return NewKeyFourSquare(r.runes("alphabet1", AlphabetL25), r.runes("alphabet2", ""), r.runes("alphabet3", ""), r.runes("alphabet4", AlphabetL25))
+		}, NewFourSquare)
+	register(CipherInfo{Name: "hill", Category: CategoryPolygraphic, Alphabet: AlphabetL, Params: []ParamInfo{alphabet, {"matrix", ParamMatrix}}},
+		func(r *paramReader) *KeyHill { return NewKeyHill(r.runes("alphabet", AlphabetL), r.matrix("matrix")) }, NewHill)
+}
@@ -0,0 +1,531 @@
+package classical
+
+import (
+	"cryptochev/utils"
+	"fmt"
+	"strings"
+)
+
+type RotorStepping string
+const (
+	SteppingOdometer RotorStepping = "odometer"
+	SteppingEnigma RotorStepping = "enigma"
+	SteppingSIGABA RotorStepping = "sigaba"
+)
+
+var rotorSteppings = map[RotorStepping]func(*rotorMachine){
+	SteppingOdometer: stepOdometer,
+	SteppingEnigma: stepEnigma,
+	SteppingSIGABA: stepSIGABA,
+}
+
+// A zero Ring stands for the first rune of the alphabet. A reversed rotor is inserted the other
+// way around, its wiring is mirrored and it steps backwards. Stators never step.
+type KeyRotor struct {
+	Wiring []rune
+	Notches []rune
+	Ring rune
+	Position rune
+	Reversed bool
+	Stator bool
+}
+
+func NewKeyRotorMachine(alphabet, entry []rune, rotors []KeyRotor, reflector []rune, stepping RotorStepping) *KeyRotorMachine {
+	return &KeyRotorMachine{Alphabet: alphabet, Entry: entry, Rotors: rotors, Reflector: reflector, Stepping: stepping}
+}
+func NewRotorMachine(text []rune, key *KeyRotorMachine) *RotorMachine {
+	return &RotorMachine{Cipher: &CipherClassical[KeyRotorMachine]{Text: text, Key: key}}
+}
+func EncryptRotorMachine(key *KeyRotorMachine, text []rune) ([]rune, error) { return cryptPure(verifyRotorMachine, checkRotorMachine, pureRotorMachine, key, text, true) }
+func DecryptRotorMachine(key *KeyRotorMachine, text []rune) ([]rune, error) { return cryptPure(verifyRotorMachine, checkRotorMachine, pureRotorMachine, key, text, false) }
+
+func pureRotorMachine(k *KeyRotorMachine, text []rune, encrypt bool, _ *Random) ([]rune, error) { return cryptRotorMachine(text, k, encrypt), nil }
+
+// Rotors go from left to right and the signal enters on the right through the entry wiring, an
+// empty entry wires straight through. Without reflector the signal leaves on the left and
+// decryption runs the rotors backwards. Control and Index are the two extra banks driving the
+// SIGABA stepping.
+type KeyRotorMachine struct {
+	Alphabet []rune
+	Entry []rune
+	Rotors []KeyRotor
+	Reflector []rune
+	Stepping RotorStepping
+	Control []KeyRotor
+	Index []KeyRotor
+}
+
+type RotorMachine struct { Cipher *CipherClassical[KeyRotorMachine] }
+func (c *RotorMachine) GetText() []rune { return c.Cipher.Text }
+func (c *RotorMachine) GetErrors() []error { return c.Cipher.Errors }
+func (c *RotorMachine) SetText(text []rune) { c.Cipher.Text = text }
+func (c *RotorMachine) Encrypt() { c.Cipher.apply(c.Cipher.Key.Alphabet, true, c.Cipher.pure(pureRotorMachine, true)) }
+func (c *RotorMachine) Decrypt() { c.Cipher.apply(c.Cipher.Key.Alphabet, true, c.Cipher.pure(pureRotorMachine, false)) }
+func (c *RotorMachine) EncryptE() error { return c.Cipher.cryptE(verifyRotorMachine, checkRotorMachine, true, c.Encrypt) }
+func (c *RotorMachine) DecryptE() error { return c.Cipher.cryptE(verifyRotorMachine, checkRotorMachine, false, c.Decrypt) }
+func (c *RotorMachine) Verify() bool { return c.Cipher.verify(verifyRotorMachine) }
+
+func verifyRotorMachine(k *KeyRotorMachine) []error {
+	errs := collectErrors(verifyAlphabet("alphabet", k.Alphabet))
+	if len(errs) > 0 {
+		return errs
+	}
+
+	if len(k.Entry) > 0 {
+		errs = append(errs, verifyPermutation("entry", k.Entry, k.Alphabet)...)
+	}
+
+	if len(k.Reflector) > 0 {
+		errs = append(errs, verifyPermutation("reflector", k.Reflector, k.Alphabet)...)
+		amap := buildIndexMap(k.Alphabet)
+		for i, r := range k.Reflector {
+			if j, found := amap[r]; found && j < len(k.Reflector) && (i == j || amap[k.Reflector[j]] != i) {
+				errs = append(errs, keyError("reflector does not swap %q with %q", k.Alphabet[i], r))
+				break
+			}
+		}
+	}
+
+	if len(k.Rotors) == 0 {
+		errs = append(errs, keyError("rotor machine has no rotors"))
+	}
+
+	errs = append(errs, verifyRotors("rotor", k.Rotors, k.Alphabet)...)
+
+	moving := 0
+	for _, r := range k.Rotors {
+		if !r.Stator {
+			moving++
+		}
+	}
+
+	switch k.Stepping {
+	case SteppingOdometer:
+	case SteppingEnigma:
+		if moving < 3 {
+			errs = append(errs, keyError("Enigma stepping needs 3 moving rotors, got %d", moving))
+		}
+	case SteppingSIGABA:
+		if len(k.Alphabet) != 26 || len(k.Rotors) != 5 || len(k.Control) != 5 || len(k.Index) != 5 || len(k.Reflector) > 0 {
+			errs = append(errs, keyError("SIGABA stepping needs 26 runes, 5 cipher, 5 control and 5 index rotors and no reflector"))
+		}
+		errs = append(errs, verifyRotors("control rotor", k.Control, k.Alphabet)...)
+		errs = append(errs, verifyRotors("index rotor", k.Index, []rune("0123456789"))...)
+	default:
+		errs = append(errs, keyError("unknown stepping %q", k.Stepping))
+	}
+
+	return errs
+}
+
+func verifyPermutation(name string, rs, alphabet []rune) []error {
+	return collectErrors(
+		verifyLength(name, rs, len(alphabet)),
+		verifyAlphabet(name, rs),
+		verifyRunesIn(name, rs, alphabet),
+	)
+}
+
+func verifyRotors(name string, rotors []KeyRotor, alphabet []rune) []error {
+	errs := make([]error, 0)
+
+	for i, r := range rotors {
+		rname := fmt.Sprintf("%s %d", name, i + 1)
+		errs = append(errs, verifyPermutation(rname + " wiring", r.Wiring, alphabet)...)
+		errs = append(errs, collectErrors(
+			verifyRunesIn(rname + " notches", r.Notches, alphabet),
+			verifyRunesIn(rname + " position", []rune{r.Position}, alphabet),
+		)...)
+
+		if r.Ring != 0 {
+			errs = append(errs, collectErrors(verifyRunesIn(rname + " ring", []rune{r.Ring}, alphabet))...)
+		}
+	}
+
+	return errs
+}
+
+func checkRotorMachine(k *KeyRotorMachine, text []rune, encrypt bool) error { return checkAlphabet(text, k.Alphabet) }
+
+type rotor struct {
+	forward []int
+	backward []int
+	notches []bool
+	ring int
+	position int
+	reversed bool
+	stator bool
+}
+
+func newRotor(k *KeyRotor, alphabet []rune) *rotor {
+	n := len(alphabet)
+	amap := buildIndexMap(alphabet)
+	r := &rotor{
+		forward: make([]int, n),
+		backward: make([]int, n),
+		notches: make([]bool, n),
+		ring: amap[k.Ring],
+		position: amap[k.Position],
+		reversed: k.Reversed,
+		stator: k.Stator,
+	}
+
+	for i, w := range k.Wiring {
+		if k.Reversed {
+			r.forward[utils.Mod(-amap[w], n)] = utils.Mod(-i, n)
+		} else {
+			r.forward[i] = amap[w]
+		}
+	}
+
+	for i, j := range r.forward {
+		r.backward[j] = i
+	}
+
+	for _, notch := range k.Notches {
+		r.notches[amap[notch]] = true
+	}
+
+	return r
+}
+
+func (r *rotor) atNotch() bool { return r.notches[r.position] }
+
+func (r *rotor) advance() {
+	if r.reversed {
+		r.position = utils.Mod(r.position - 1, len(r.forward))
+	} else {
+		r.position = (r.position + 1) % len(r.forward)
+	}
+}
+
+// Right to left through the rotor.
+func (r *rotor) in(x int) int {
+	shift := r.position - r.ring
+	return utils.Mod(r.forward[utils.Mod(x + shift, len(r.forward))] - shift, len(r.forward))
+}
+
+// Left to right through the rotor.
+func (r *rotor) out(x int) int {
+	shift := r.position - r.ring
+	return utils.Mod(r.backward[utils.Mod(x + shift, len(r.backward))] - shift, len(r.backward))
+}
+
+type rotorMachine struct {
+	entry []int
+	exit []int
+	rotors []*rotor
+	moving []*rotor
+	reflector []int
+	step func(*rotorMachine)
+	control []*rotor
+	index []*rotor
+}
+
+func newRotorMachine(k *KeyRotorMachine) *rotorMachine {
+	amap := buildIndexMap(k.Alphabet)
+	digits := []rune("0123456789")
+	m := &rotorMachine{step: rotorSteppings[k.Stepping]}
+
+	if len(k.Entry) > 0 {
+		m.entry, m.exit = make([]int, len(k.Alphabet)), make([]int, len(k.Alphabet))
+		for i, r := range k.Entry {
+			m.entry[i], m.exit[amap[r]] = amap[r], i
+		}
+	}
+
+	if len(k.Reflector) > 0 {
+		m.reflector = make([]int, len(k.Alphabet))
+		for i, r := range k.Reflector {
+			m.reflector[i] = amap[r]
+		}
+	}
+
+	for i := range k.Rotors {
+		r := newRotor(&k.Rotors[i], k.Alphabet)
+		m.rotors = append(m.rotors, r)
+		if !r.stator {
+			m.moving = append(m.moving, r)
+		}
+	}
+
+	for i := range k.Control {
+		m.control = append(m.control, newRotor(&k.Control[i], k.Alphabet))
+	}
+
+	for i := range k.Index {
+		m.index = append(m.index, newRotor(&k.Index[i], digits))
+	}
+
+	return m
+}
+
+func (m *rotorMachine) crypt(x int, encrypt bool) int {
+	m.step(m)
+
+	if m.entry != nil {
+		x = m.entry[x]
+	}
+
+	if m.reflector != nil || encrypt {
+		for i := len(m.rotors) - 1; i >= 0; i-- {
+			x = m.rotors[i].in(x)
+		}
+	}
+
+	if m.reflector != nil {
+		x = m.reflector[x]
+	}
+
+	if m.reflector != nil || !encrypt {
+		for _, r := range m.rotors {
+			x = r.out(x)
+		}
+	}
+
+	if m.exit != nil {
+		x = m.exit[x]
+	}
+
+	return x
+}
+
+func cryptRotorMachine(text []rune, k *KeyRotorMachine, encrypt bool) []rune {
+	amap := buildIndexMap(k.Alphabet)
+	m := newRotorMachine(k)
+	result := make([]rune, len(text))
+
+	for i, r := range text {
+		result[i] = k.Alphabet[m.crypt(amap[r], encrypt)]
+	}
+
+	return result
+}
+
+// The rightmost moving rotor steps on every rune and carries to its left neighbour from its notches.
+func stepOdometer(m *rotorMachine) {
+	for i := len(m.moving) - 1; i >= 0; i-- {
+		carry := m.moving[i].atNotch()
+		m.moving[i].advance()
+
+		if !carry {
+			return
+		}
+	}
+}
+
+// Only the three rightmost moving rotors step. The middle one steps again on its own notch,
+// which gives the double step of the Enigma.
+func stepEnigma(m *rotorMachine) {
+	right, middle, left := m.moving[len(m.moving) - 1], m.moving[len(m.moving) - 2], m.moving[len(m.moving) - 3]
+
+	if middle.atNotch() {
+		middle.advance()
+		left.advance()
+	} else if right.atNotch() {
+		middle.advance()
+	}
+
+	right.advance()
+}
+
+// Control outputs wired together into the 10 index inputs, A goes to 9 and no output goes to 0.
+var sigabaIndexInputs = [26]int{9, 1, 2, 3, 3, 4, 4, 4, 5, 5, 5, 6, 6, 6, 6, 7, 7, 7, 7, 7, 8, 8, 8, 8, 8, 8}
+
+// Four live contacts F, G, H and I cross the control bank, the index bank and step from one to
+// four cipher rotors, one for each pair of index outputs 1-2, 3-4, 5-6, 7-8 and 9-0 from the left.
+// Then the three middle control rotors move like an odometer carried at O, the fast one in the
+// middle, then the right and the left one.
+func stepSIGABA(m *rotorMachine) {
+	active := make([]bool, 10)
+	for x := 5; x <= 8; x++ {
+		y := x
+		for i := len(m.control) - 1; i >= 0; i-- {
+			y = m.control[i].in(y)
+		}
+		active[sigabaIndexInputs[y]] = true
+	}
+
+	stepped := make([]bool, len(m.rotors))
+	for y, on := range active {
+		if on {
+			for i := len(m.index) - 1; i >= 0; i-- {
+				y = m.index[i].in(y)
+			}
+			stepped[(y + 9) % 10 / 2] = true
+		}
+	}
+
+	for i, r := range m.rotors {
+		if stepped[i] {
+			r.advance()
+		}
+	}
+
+	fast, medium, slow := m.control[2], m.control[3], m.control[1]
+	const o = 'O' - 'A'
+
+	if fast.position == o {
+		if medium.position == o {
+			slow.advance()
+		}
+		medium.advance()
+	}
+	fast.advance()
+}
+
+func verifyRotorNames[V any](names []string, rotors map[string]V) []error {
+	errs := make([]error, 0)
+	seen := make(map[string]bool, len(names))
+
+	for _, name := range names {
+		if _, found := rotors[name]; !found {
+			errs = append(errs, keyError("unknown rotor %q", name))
+		} else if seen[name] {
+			errs = append(errs, keyError("rotor %s is used more than once", name))
+		}
+		seen[name] = true
+	}
+
+	return errs
+}
+
+func verifyPlugboard(plugboard, alphabet []rune) []error {
+	plugs := plugboardPairs(plugboard)
+	if len(plugs) == 0 {
+		return nil
+	}
+
+	errs := collectErrors(verifyAlphabet("plugboard", plugs), verifyRunesIn("plugboard", plugs, alphabet))
+	if len(plugs) % 2 != 0 {
+		errs = append(errs, keyError("plugboard has an unpaired rune %q", plugs[len(plugs) - 1]))
+	}
+
+	return errs
+}
+
+func plugboardPairs(plugboard []rune) []rune {
+	plugs := make([]rune, 0, len(plugboard))
+	for _, r := range plugboard {
+		if r != ' ' {
+			plugs = append(plugs, r)
+		}
+	}
+
+	return plugs
+}
+
+// The plugboard is the entry wiring, as it swaps pairs it undoes itself on the way out.
+func plugboardEntry(plugboard, alphabet []rune) []rune {
+	amap := buildIndexMap(alphabet)
+	entry := append([]rune{}, alphabet...)
+	plugs := plugboardPairs(plugboard)
+
+	for i := 0; i + 1 < len(plugs); i += 2 {
+		entry[amap[plugs[i]]], entry[amap[plugs[i + 1]]] = plugs[i + 1], plugs[i]
+	}
+
+	return entry
+}
+
+// The rotor wirings used by the usual SIGABA simulators, any of the ten alphabet rotors can go in
+// the cipher or the control bank.
+var sigabaRotors = map[string]string{
+	"0": "YCHLQSUGBDIXNZKERPVJTAWFOM",
+	"1": "INPXBWETGUYSAOCHVLDMQKZJFR",
+	"2": "WNDRIOZPTAXHFJYQBMSVEKUCGL",
+	"3": "TZGHOBKRVUXLQDMPNFWCJYEIAS",
+	"4": "YWTAHRQJVLCEXUNGBIPZMSDFOK",
+	"5": "QSLRBTEKOGAICFWYVMHJNXZUDP",
+	"6": "CHJDQIGNBSAKVTUOXFWLEPRMZY",
+	"7": "CDFAJXTIMNBEQHSUGRYLWZKVPO",
+	"8": "XHFESZDNRBCGKQIJLTVMUOYAPW",
+	"9": "EZJQXMOGYTCSFRIUPVNADLHWBK",
+}
+
+var sigabaIndexRotors = map[string]string{
+	"0": "7591482630",
+	"1": "3810592764",
+	"2": "4086153297",
+	"3": "3980526174",
+	"4": "6497135280",
+}
+
+func NewKeySIGABA(cipher, control, index []string, cipherPositions, controlPositions, indexPositions []rune) *KeySIGABA {
+	return &KeySIGABA{Cipher: cipher, Control: control, Index: index, CipherPositions: cipherPositions, ControlPositions: controlPositions, IndexPositions: indexPositions}
+}
+func NewSIGABA(text []rune, key *KeySIGABA) *SIGABA { return &SIGABA{Cipher: &CipherClassical[KeySIGABA]{Text: text, Key: key}} }
+func EncryptSIGABA(key *KeySIGABA, text []rune) ([]rune, error) { return cryptPure(verifySIGABA, checkSIGABA, pureSIGABA, key, text, true) }
+func DecryptSIGABA(key *KeySIGABA, text []rune) ([]rune, error) { return cryptPure(verifySIGABA, checkSIGABA, pureSIGABA, key, text, false) }
+
+func pureSIGABA(k *KeySIGABA, text []rune, encrypt bool, _ *Random) ([]rune, error) { return cryptRotorMachine(text, sigabaRotorMachine(k), encrypt), nil }
+
+// Each bank takes 5 rotors from left to right. Cipher and control rotors are named 0 to 9 and
+// share the same set, a trailing R inserts the rotor reversed. Index rotors are named 0 to 4 and
+// their positions are digits.
+type KeySIGABA struct {
+	Cipher []string
+	Control []string
+	Index []string
+	CipherPositions []rune
+	ControlPositions []rune
+	IndexPositions []rune
+}
+
+type SIGABA struct { Cipher *CipherClassical[KeySIGABA] }
+func (c *SIGABA) GetText() []rune { return c.Cipher.Text }
+func (c *SIGABA) GetErrors() []error { return c.Cipher.Errors }
+func (c *SIGABA) SetText(text []rune) { c.Cipher.Text = text }
+func (c *SIGABA) Encrypt() { c.Cipher.apply([]rune(AlphabetL), true, c.Cipher.pure(pureSIGABA, true)) }
+func (c *SIGABA) Decrypt() { c.Cipher.apply([]rune(AlphabetL), true, c.Cipher.pure(pureSIGABA, false)) }
+func (c *SIGABA) EncryptE() error { return c.Cipher.cryptE(verifySIGABA, checkSIGABA, true, c.Encrypt) }
+func (c *SIGABA) DecryptE() error { return c.Cipher.cryptE(verifySIGABA, checkSIGABA, false, c.Decrypt) }
+func (c *SIGABA) Verify() bool { return c.Cipher.verify(verifySIGABA) }
+
+func verifySIGABA(k *KeySIGABA) []error {
+	alphabet := []rune(AlphabetL)
+	errs := collectErrors(
+		verifyLength("cipher positions", k.CipherPositions, 5),
+		verifyRunesIn("cipher positions", k.CipherPositions, alphabet),
+		verifyLength("control positions", k.ControlPositions, 5),
+		verifyRunesIn("control positions", k.ControlPositions, alphabet),
+		verifyLength("index positions", k.IndexPositions, 5),
+		verifyRunesIn("index positions", k.IndexPositions, []rune("0123456789")),
+	)
+
+	if len(k.Cipher) != 5 || len(k.Control) != 5 || len(k.Index) != 5 {
+		errs = append(errs, keyError("SIGABA takes 5 rotors in each bank, got %d, %d and %d", len(k.Cipher), len(k.Control), len(k.Index)))
+	}
+
+	names := make([]string, 0, len(k.Cipher) + len(k.Control))
+	for _, name := range append(append([]string{}, k.Cipher...), k.Control...) {
+		names = append(names, strings.TrimSuffix(name, "R"))
+	}
+	errs = append(errs, verifyRotorNames(names, sigabaRotors)...)
+
+	return append(errs, verifyRotorNames(k.Index, sigabaIndexRotors)...)
+}
+
+func checkSIGABA(k *KeySIGABA, text []rune, encrypt bool) error { return checkAlphabet(text, []rune(AlphabetL)) }
+
+func sigabaBank(names []string, positions []rune) []KeyRotor {
+	rotors := make([]KeyRotor, len(names))
+	for i, name := range names {
+		base := strings.TrimSuffix(name, "R")
+		rotors[i] = KeyRotor{Wiring: []rune(sigabaRotors[base]), Position: positions[i], Reversed: base != name}
+	}
+
+	return rotors
+}
+
+func sigabaRotorMachine(k *KeySIGABA) *KeyRotorMachine {
+	index := make([]KeyRotor, len(k.Index))
+	for i, name := range k.Index {
+		index[i] = KeyRotor{Wiring: []rune(sigabaIndexRotors[name]), Ring: '0', Position: k.IndexPositions[i]}
+	}
+
+	m := NewKeyRotorMachine([]rune(AlphabetL), nil, sigabaBank(k.Cipher, k.CipherPositions), nil, SteppingSIGABA)
+	m.Control, m.Index = sigabaBank(k.Control, k.ControlPositions), index
+
+	return m
+}
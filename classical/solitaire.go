@@ -0,0 +1,201 @@
+package classical
+
+import (
+	"cryptochev/utils"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Cards are numbered in bridge order, clubs 1 to 13, diamonds, hearts then spades up to 52,
+// followed by the A and B jokers.
+const (
+	SolitaireJokerA = 53
+	SolitaireJokerB = 54
+)
+
+var solitaireRanks = []string{"A", "2", "3", "4", "5", "6", "7", "8", "9", "10", "J", "Q", "K"}
+var solitaireSuits = "CDHS"
+
+func NewKeySolitaire(deck []int, passphrase []rune) *KeySolitaire { return &KeySolitaire{Deck: deck, Passphrase: passphrase} }
+func NewSolitaire(text []rune, key *KeySolitaire) *Solitaire { return &Solitaire{Cipher: &CipherClassical[KeySolitaire]{Text: text, Key: key}} }
+func EncryptSolitaire(key *KeySolitaire, text []rune) ([]rune, error) { return cryptPure(verifySolitaire, checkSolitaire, pureSolitaire, key, text, true) }
+func DecryptSolitaire(key *KeySolitaire, text []rune) ([]rune, error) { return cryptPure(verifySolitaire, checkSolitaire, pureSolitaire, key, text, false) }
+
+func pureSolitaire(k *KeySolitaire, text []rune, encrypt bool, _ *Random) ([]rune, error) { return cryptVigenere(text, []rune(AlphabetL), solitaireKeystream(k, len(text)), encrypt), nil }
+
+// Deck is the ordering of the 54 cards from the top, an empty deck is in bridge order. The
+// passphrase then keys the deck, it may be empty.
+type KeySolitaire struct {
+	Deck []int
+	Passphrase []rune
+}
+
+type Solitaire struct { Cipher *CipherClassical[KeySolitaire] }
+func (c *Solitaire) GetText() []rune { return c.Cipher.Text }
+func (c *Solitaire) GetErrors() []error { return c.Cipher.Errors }
+func (c *Solitaire) SetText(text []rune) { c.Cipher.Text = text }
+func (c *Solitaire) Encrypt() { c.Cipher.apply([]rune(AlphabetL), true, c.Cipher.pure(pureSolitaire, true)) }
+func (c *Solitaire) Decrypt() { c.Cipher.apply([]rune(AlphabetL), true, c.Cipher.pure(pureSolitaire, false)) }
+func (c *Solitaire) EncryptE() error { return c.Cipher.cryptE(verifySolitaire, checkSolitaire, true, c.Encrypt) }
+func (c *Solitaire) DecryptE() error { return c.Cipher.cryptE(verifySolitaire, checkSolitaire, false, c.Decrypt) }
+func (c *Solitaire) Verify() bool { return c.Cipher.verify(verifySolitaire) }
+
+func verifySolitaire(k *KeySolitaire) []error {
+	errs := collectErrors(verifyRunesIn("passphrase", k.Passphrase, []rune(AlphabetL)))
+
+	if len(k.Deck) == 0 {
+		return errs
+	}
+
+	if len(k.Deck) != SolitaireJokerB {
+		errs = append(errs, keyError("deck has %d cards, expected %d", len(k.Deck), SolitaireJokerB))
+	}
+
+	seen := make(map[int]bool, len(k.Deck))
+	for _, card := range k.Deck {
+		if card < 1 || card > SolitaireJokerB {
+			errs = append(errs, keyError("deck contains card %d which is not between 1 and %d", card, SolitaireJokerB))
+		} else if seen[card] {
+			errs = append(errs, keyError("deck contains card %d more than once", card))
+		}
+		seen[card] = true
+	}
+
+	return errs
+}
+
+func checkSolitaire(k *KeySolitaire, text []rune, encrypt bool) error { return checkAlphabet(text, []rune(AlphabetL)) }
+
+// Cards are written as a rank and a suit like AC, 10D or KS, or as their number. The jokers are A
+// and B.
+func ParseSolitaireDeck(cards []string) ([]int, error) {
+	deck := make([]int, len(cards))
+
+	for i, card := range cards {
+		switch {
+		case card == "A":
+			deck[i] = SolitaireJokerA
+		case card == "B":
+			deck[i] = SolitaireJokerB
+		case len(card) > 1 && strings.ContainsAny(card[len(card) - 1:], solitaireSuits):
+			rank := utils.IndexOf(solitaireRanks, card[:len(card) - 1])
+			if rank < 0 {
+				return nil, fmt.Errorf("%w: card %d: %q is not a rank", ErrInvalidKey, i + 1, card[:len(card) - 1])
+			}
+			deck[i] = strings.Index(solitaireSuits, card[len(card) - 1:]) * 13 + rank + 1
+		default:
+			n, err := strconv.Atoi(card)
+			if err != nil {
+				return nil, fmt.Errorf("%w: card %d: %q is not a card", ErrInvalidKey, i + 1, card)
+			}
+			deck[i] = n
+		}
+	}
+
+	return deck, nil
+}
+
+type solitaireDeck []int
+
+func newSolitaireDeck(k *KeySolitaire) solitaireDeck {
+	d := make(solitaireDeck, SolitaireJokerB)
+	for i := range d {
+		d[i] = i + 1
+	}
+	if len(k.Deck) > 0 {
+		copy(d, k.Deck)
+	}
+
+	for _, r := range k.Passphrase {
+		d.step()
+		d.countCut(int(r - 'A') + 1)
+	}
+
+	return d
+}
+
+func (d solitaireDeck) index(card int) int {
+	for i, c := range d {
+		if c == card {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// A joker moved down from the bottom goes just below the top card, the deck is a loop whose top
+// card never changes during the move.
+func (d solitaireDeck) moveDown(card, n int) {
+	i := d.index(card)
+	for ; n > 0; n-- {
+		if i == len(d) - 1 {
+			copy(d[2:], d[1:i])
+			d[1] = card
+			i = 1
+		} else {
+			d[i], d[i + 1] = d[i + 1], d[i]
+			i++
+		}
+	}
+}
+
+func (d solitaireDeck) value(card int) int {
+	if card == SolitaireJokerB {
+		return SolitaireJokerA
+	}
+
+	return card
+}
+
+func (d solitaireDeck) tripleCut() {
+	first, second := d.index(SolitaireJokerA), d.index(SolitaireJokerB)
+	if first > second {
+		first, second = second, first
+	}
+
+	cut := make([]int, 0, len(d))
+	cut = append(cut, d[second + 1:]...)
+	cut = append(cut, d[first:second + 1]...)
+	cut = append(cut, d[:first]...)
+	copy(d, cut)
+}
+
+// The count cut moves the top n cards just above the bottom card.
+func (d solitaireDeck) countCut(n int) {
+	bottom := len(d) - 1
+	cut := make([]int, 0, len(d))
+	cut = append(cut, d[n:bottom]...)
+	cut = append(cut, d[:n]...)
+	copy(d, cut)
+}
+
+func (d solitaireDeck) step() {
+	d.moveDown(SolitaireJokerA, 1)
+	d.moveDown(SolitaireJokerB, 2)
+	d.tripleCut()
+	d.countCut(d.value(d[len(d) - 1]))
+}
+
+// The output card is found by counting down the value of the top card, a joker gives no output.
+func (d solitaireDeck) next() int {
+	for {
+		d.step()
+		if card := d[d.value(d[0])]; card < SolitaireJokerA {
+			return card
+		}
+	}
+}
+
+// Cards go around the alphabet twice, so 1 and 27 both shift A to B.
+func solitaireKeystream(k *KeySolitaire, length int) []rune {
+	d := newSolitaireDeck(k)
+	alphabet := []rune(AlphabetL)
+	result := make([]rune, length)
+	for i := range result {
+		result[i] = alphabet[d.next() % len(alphabet)]
+	}
+
+	return result
+}
@@ -0,0 +1,415 @@
+package classical
+
+import (
+	"cryptochev/utils"
+	"errors"
+	"fmt"
+	"io"
+	"unicode"
+	"unicode/utf8"
+)
+
+var ErrNotStreamable = errors.New("cipher cannot be streamed")
+
+type runeStream struct {
+	amap map[rune]int
+	crypt func(r rune) rune
+	skip func()
+	policy *Policy
+	pos int
+	marks []streamMark
+}
+
+// A writer marks the end of each rune in its input and output, so that a short write can be
+// counted back to the input which was written.
+type streamMark struct {
+	in, out int
+}
+
+func newRuneStream(c ICipherClassical, encrypt bool) (*runeStream, error) {
+	switch c := c.(type) {
+	case *Caesar:
+		key, err := streamKey(c.Cipher, verifyCaesar)
+		if err != nil {
+			return nil, err
+		}
+		return newCaesarStream(key.Shift, encrypt), nil
+	case *ROT13:
+		return newCaesarStream(13, true), nil
+	case *Shift:
+		key, err := streamKey(c.Cipher, verifyShift)
+		if err != nil {
+			return nil, err
+		}
+		shift, _ := utils.SwapIf(-rune(key.Shift), rune(key.Shift), encrypt)
+		return &runeStream{crypt: func(r rune) rune { return r + shift }}, nil
+	case *ShiftAlphabet:
+		key, err := streamKey(c.Cipher, verifyShiftAlphabet)
+		if err != nil {
+			return nil, err
+		}
+		shift, _ := utils.SwapIf(-key.Shift, key.Shift, encrypt)
+		return newAlphabetStream(key.Alphabet, c.Cipher.Policy, func(i int) rune {
+			return key.Alphabet[utils.Mod(i + shift, len(key.Alphabet))]
+		}), nil
+	case *Substitute:
+		key, err := streamKey(c.Cipher, verifySubstitute)
+		if err != nil {
+			return nil, err
+		}
+		if encrypt {
+			return newAlphabetStream(key.Alphabet, c.Cipher.Policy, func(i int) rune { return key.SAlphabet[i] }), nil
+		}
+		return newAlphabetStream(key.SAlphabet, c.Cipher.Policy, func(i int) rune { return key.Alphabet[i] }), nil
+	case *Affine:
+		key, err := streamKey(c.Cipher, verifyAffine)
+		if err != nil {
+			return nil, err
+		}
+		return newAffineStream(key.Alphabet, key.A, key.B, encrypt, c.Cipher.Policy), nil
+	case *Atbash:
+		key, err := streamKey(c.Cipher, verifyAtbash)
+		if err != nil {
+			return nil, err
+		}
+		return newAffineStream(key.Alphabet, -1, -1, true, c.Cipher.Policy), nil
+	case *Vigenere:
+		key, err := streamKey(c.Cipher, verifyVigenere)
+		if err != nil {
+			return nil, err
+		}
+		return newVigenereStream(key.Alphabet, key.Key, encrypt, c.Cipher.Policy), nil
+	case *VigenereBeaufort:
+		key, err := streamKey(c.Cipher, verifyVigenere)
+		if err != nil {
+			return nil, err
+		}
+		return newVigenereStream(key.Alphabet, key.Key, !encrypt, c.Cipher.Policy), nil
+	case *VigenereGronsfeld:
+		key, err := streamKey(c.Cipher, verifyGronsfeld)
+		if err != nil {
+			return nil, err
+		}
+		return newVigenereStream(key.Alphabet, gronsfeldToVigenereKey(key.Alphabet, key.Key), encrypt, c.Cipher.Policy), nil
+	case *Beaufort:
+		key, err := streamKey(c.Cipher, verifyBeaufort)
+		if err != nil {
+			return nil, err
+		}
+		return newBeaufortStream(key.Alphabet, key.Key, c.Cipher.Policy), nil
+	case *Autokey:
+		key, err := streamKey(c.Cipher, verifyAutokey)
+		if err != nil {
+			return nil, err
+		}
+		return newAutokeyStream(key.Alphabet, key.Primer, encrypt, c.Cipher.Policy), nil
+	case *Chaocipher:
+		key, err := streamKey(c.Cipher, verifyChaocipher)
+		if err != nil {
+			return nil, err
+		}
+		return newChaocipherStream(key.Left, key.Right, encrypt, c.Cipher.Policy), nil
+	}
+
+	return nil, fmt.Errorf("%w: %T", ErrNotStreamable, c)
+}
+
+func streamKey[K CipherClassicalKey](c *CipherClassical[K], verify func(*K) []error) (*K, error) {
+	if c.Key == nil {
+		return nil, keyError("missing key")
+	}
+
+	if errs := verify(c.Key); len(errs) > 0 {
+		return nil, errs[0]
+	}
+
+	return c.Key, nil
+}
+
+func newCaesarStream(shift int, encrypt bool) *runeStream {
+	shift, _ = utils.SwapIf(-shift, shift, encrypt)
+	return &runeStream{crypt: func(r rune) rune { return shiftCaesar([]rune{r}, shift)[0] }}
+}
+
+func newAlphabetStream(alphabet []rune, policy *Policy, crypt func(i int) rune) *runeStream {
+	amap := buildIndexMap(alphabet)
+	return &runeStream{amap: amap, policy: policy, crypt: func(r rune) rune { return crypt(amap[r]) }}
+}
+
+func newAffineStream(alphabet []rune, a, b int, encrypt bool, policy *Policy) *runeStream {
+	n := len(alphabet)
+	ia := utils.ModInverse(utils.Mod(a, n), n)
+
+	return newAlphabetStream(alphabet, policy, func(i int) rune {
+		if encrypt {
+			return alphabet[utils.Mod(a * i + b, n)]
+		}
+		return alphabet[utils.Mod(ia * (i - b), n)]
+	})
+}
+
+func newVigenereStream(alphabet, key []rune, encrypt bool, policy *Policy) *runeStream {
+	if len(key) == 0 {
+		key = alphabet
+	}
+
+	n := len(alphabet)
+	pos := 0
+	s := newAlphabetStream(alphabet, policy, nil)
+	s.crypt = func(r rune) rune {
+		k := s.amap[key[pos % len(key)]]
+		pos++
+		if encrypt {
+			return alphabet[(s.amap[r] + k) % n]
+		}
+		return alphabet[(s.amap[r] - k + n) % n]
+	}
+	s.skip = func() { pos++ }
+
+	return s
+}
+
+func newBeaufortStream(alphabet, key []rune, policy *Policy) *runeStream {
+	if len(key) == 0 {
+		key = alphabet
+	}
+
+	n := len(alphabet)
+	pos := 0
+	s := newAlphabetStream(alphabet, policy, nil)
+	s.crypt = func(r rune) rune {
+		k := s.amap[key[pos % len(key)]]
+		pos++
+		return alphabet[(k - s.amap[r] + n) % n]
+	}
+	s.skip = func() { pos++ }
+
+	return s
+}
+
+func newAutokeyStream(alphabet, primer []rune, encrypt bool, policy *Policy) *runeStream {
+	n := len(alphabet)
+	queue := append([]rune{}, primer...)
+	s := newAlphabetStream(alphabet, policy, nil)
+	s.crypt = func(r rune) rune {
+		k := s.amap[queue[0]]
+		queue = queue[1:]
+
+		if encrypt {
+			queue = append(queue, r)
+			return alphabet[(s.amap[r] + k) % n]
+		}
+
+		result := alphabet[(s.amap[r] - k + n) % n]
+		queue = append(queue, result)
+		return result
+	}
+
+	return s
+}
+
+func newChaocipherStream(left, right []rune, encrypt bool, policy *Policy) *runeStream {
+	left = append([]rune{}, left...)
+	right = append([]rune{}, right...)
+	from := left
+	if encrypt {
+		from = right
+	}
+
+	s := newAlphabetStream(from, policy, nil)
+	s.crypt = func(r rune) rune {
+		var index int
+		var result rune
+
+		if encrypt {
+			index = utils.IndexOf(right, r)
+			result = left[index]
+		} else {
+			index = utils.IndexOf(left, r)
+			result = right[index]
+		}
+
+		left, right = permuteChaocipher(left, right, index)
+		return result
+	}
+
+	return s
+}
+
+func (s *runeStream) next(out []byte, r rune) ([]byte, error) {
+	pos := s.pos
+	s.pos++
+
+	if _, found := s.amap[r]; s.amap == nil || found {
+		return utf8.AppendRune(out, s.crypt(r)), nil
+	}
+
+	if s.policy != nil && s.policy.KeepCase {
+		if fr := foldRune(r, s.amap); fr != r {
+			if unicode.IsLower(r) {
+				return utf8.AppendRune(out, unicode.ToLower(s.crypt(fr))), nil
+			}
+			return utf8.AppendRune(out, unicode.ToUpper(s.crypt(fr))), nil
+		}
+	}
+
+	if s.policy == nil || s.policy.Mode == PolicyReject {
+		return out, &InvalidRuneError{Position: pos, Rune: r}
+	} else if s.policy.Mode == PolicyDrop {
+		return out, nil
+	}
+
+	if s.skip != nil && !s.policy.HoldKey {
+		s.skip()
+	}
+
+	return utf8.AppendRune(out, r), nil
+}
+
+func (s *runeStream) process(out, data []byte, final bool) ([]byte, int, error) {
+	consumed := 0
+
+	for consumed < len(data) {
+		if !final && !utf8.FullRune(data[consumed:]) {
+			break
+		}
+
+		r, size := utf8.DecodeRune(data[consumed:])
+		if r == utf8.RuneError && size <= 1 {
+			return out, consumed, fmt.Errorf("%w: invalid UTF-8 at rune %d", ErrInvalidText, s.pos)
+		}
+
+		var err error
+		if out, err = s.next(out, r); err != nil {
+			return out, consumed, err
+		}
+
+		consumed += size
+		if s.marks != nil {
+			s.marks = append(s.marks, streamMark{consumed, len(out)})
+		}
+	}
+
+	return out, consumed, nil
+}
+
+type Writer struct {
+	w io.Writer
+	stream *runeStream
+	pending []byte
+	out []byte
+	err error
+}
+
+func NewEncryptWriter(w io.Writer, c ICipherClassical) (*Writer, error) { return newWriter(w, c, true) }
+func NewDecryptWriter(w io.Writer, c ICipherClassical) (*Writer, error) { return newWriter(w, c, false) }
+
+func newWriter(w io.Writer, c ICipherClassical, encrypt bool) (*Writer, error) {
+	stream, err := newRuneStream(c, encrypt)
+	if err != nil {
+		return nil, err
+	}
+
+	stream.marks = make([]streamMark, 0)
+	return &Writer{w: w, stream: stream}, nil
+}
+
+func (w *Writer) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+
+	// The runes completed from pending bytes are counted from the start of p.
+	pending := len(w.pending)
+	data := append(w.pending, p...)
+	w.stream.marks = w.stream.marks[:0]
+
+	var consumed int
+	var err error
+	w.out, consumed, err = w.stream.process(w.out[:0], data, false)
+	w.pending = append([]byte{}, data[consumed:]...)
+
+	written, werr := w.w.Write(w.out)
+	if werr == nil && written < len(w.out) {
+		werr = io.ErrShortWrite
+	}
+
+	if werr != nil {
+		err = werr
+		consumed = 0
+		for _, m := range w.stream.marks {
+			if m.out > written {
+				break
+			}
+			consumed = m.in
+		}
+	}
+
+	if err != nil {
+		w.err = err
+		if consumed < pending {
+			return 0, err
+		}
+		return consumed - pending, err
+	}
+
+	return len(p), nil
+}
+
+func (w *Writer) Close() error {
+	if w.err == nil && len(w.pending) > 0 {
+		w.err = fmt.Errorf("%w: truncated UTF-8 at rune %d", ErrInvalidText, w.stream.pos)
+	}
+
+	return w.err
+}
+
+type Reader struct {
+	r io.Reader
+	stream *runeStream
+	pending []byte
+	buf []byte
+	out []byte
+	err error
+}
+
+func NewEncryptReader(r io.Reader, c ICipherClassical) (*Reader, error) { return newReader(r, c, true) }
+func NewDecryptReader(r io.Reader, c ICipherClassical) (*Reader, error) { return newReader(r, c, false) }
+
+func newReader(r io.Reader, c ICipherClassical, encrypt bool) (*Reader, error) {
+	stream, err := newRuneStream(c, encrypt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Reader{r: r, stream: stream, buf: make([]byte, 4096)}, nil
+}
+
+func (r *Reader) Read(p []byte) (int, error) {
+	for len(r.out) == 0 && r.err == nil {
+		n, err := r.r.Read(r.buf)
+		r.pending = append(r.pending, r.buf[:n]...)
+
+		var consumed int
+		var perr error
+		r.out, consumed, perr = r.stream.process(r.out, r.pending, err == io.EOF)
+		r.pending = r.pending[consumed:]
+
+		if perr != nil {
+			r.err = perr
+		} else if err == io.EOF && len(r.pending) > 0 {
+			r.err = fmt.Errorf("%w: truncated UTF-8 at rune %d", ErrInvalidText, r.stream.pos)
+		} else if err != nil {
+			r.err = err
+		}
+	}
+
+	n := copy(p, r.out)
+	r.out = r.out[n:]
+
+	if len(r.out) == 0 && r.err != nil {
+		return n, r.err
+	}
+
+	return n, nil
+}
@@ -7,6 +7,16 @@ import (
 
 func NewKeySubstitute(alphabet, salphabet []rune) *KeySubstitute { return &KeySubstitute{Alphabet: alphabet, SAlphabet: salphabet} }
 func NewSubstitute(text []rune, key *KeySubstitute) *Substitute { return &Substitute{Cipher: &CipherClassical[KeySubstitute]{Text: text, Key: key}} }
+func EncryptSubstitute(key *KeySubstitute, text []rune) ([]rune, error) { return cryptPure(verifySubstitute, checkSubstitute, pureSubstitute, key, text, true) }
+func DecryptSubstitute(key *KeySubstitute, text []rune) ([]rune, error) { return cryptPure(verifySubstitute, checkSubstitute, pureSubstitute, key, text, false) }
+
+func pureSubstitute(k *KeySubstitute, text []rune, encrypt bool, _ *Random) ([]rune, error) {
+	if encrypt {
+		return substitute(text, k.Alphabet, k.SAlphabet), nil
+	}
+
+	return substitute(text, k.SAlphabet, k.Alphabet), nil
+}
 
 type KeySubstitute struct {
 	Alphabet []rune
@@ -16,9 +26,28 @@ type KeySubstitute struct {
 type Substitute struct { Cipher *CipherClassical[KeySubstitute] }
 func (c *Substitute) GetText() []rune { return c.Cipher.Text }
 func (c *Substitute) GetErrors() []error { return c.Cipher.Errors }
-func (c *Substitute) Encrypt() { c.Cipher.Text = substitute(c.Cipher.Text, c.Cipher.Key.Alphabet, c.Cipher.Key.SAlphabet) }
-func (c *Substitute) Decrypt() { c.Cipher.Text = substitute(c.Cipher.Text, c.Cipher.Key.SAlphabet, c.Cipher.Key.Alphabet) }
-func (c *Substitute) Verify() bool { return true }
+func (c *Substitute) SetText(text []rune) { c.Cipher.Text = text }
+func (c *Substitute) Encrypt() { c.Cipher.apply(c.Cipher.Key.Alphabet, false, c.Cipher.pure(pureSubstitute, true)) }
+func (c *Substitute) Decrypt() { c.Cipher.apply(c.Cipher.Key.SAlphabet, false, c.Cipher.pure(pureSubstitute, false)) }
+func (c *Substitute) EncryptE() error { return c.Cipher.cryptE(verifySubstitute, checkSubstitute, true, c.Encrypt) }
+func (c *Substitute) DecryptE() error { return c.Cipher.cryptE(verifySubstitute, checkSubstitute, false, c.Decrypt) }
+func (c *Substitute) Verify() bool { return c.Cipher.verify(verifySubstitute) }
+
+func verifySubstitute(k *KeySubstitute) []error {
+	return collectErrors(
+		verifyAlphabet("alphabet", k.Alphabet),
+		verifyAlphabet("substitution alphabet", k.SAlphabet),
+		verifySameLength("alphabet", k.Alphabet, "substitution alphabet", k.SAlphabet),
+	)
+}
+
+func checkSubstitute(k *KeySubstitute, text []rune, encrypt bool) error {
+	if encrypt {
+		return checkAlphabet(text, k.Alphabet)
+	}
+
+	return checkAlphabet(text, k.SAlphabet)
+}
 
 func substitute(text, alphabet, salphabet []rune) []rune {
 	result := make([]rune, len(text))
@@ -33,6 +62,16 @@ func substitute(text, alphabet, salphabet []rune) []rune {
 
 func NewKeyShift(shift int) *KeyShift { return &KeyShift{Shift: shift} }
 func NewShift(text []rune, key *KeyShift) *Shift { return &Shift{Cipher: &CipherClassical[KeyShift]{Text: text, Key: key}} }
+func EncryptShift(key *KeyShift, text []rune) ([]rune, error) { return cryptPure(verifyShift, nil, pureShift, key, text, true) }
+func DecryptShift(key *KeyShift, text []rune) ([]rune, error) { return cryptPure(verifyShift, nil, pureShift, key, text, false) }
+
+func pureShift(k *KeyShift, text []rune, encrypt bool, _ *Random) ([]rune, error) {
+	if encrypt {
+		return shift(text, k.Shift), nil
+	}
+
+	return shift(text, -k.Shift), nil
+}
 
 type KeyShift struct { 
 	Shift int 
@@ -41,22 +80,38 @@ type KeyShift struct {
 type Shift struct { Cipher *CipherClassical[KeyShift] }
 func (c *Shift) GetText() []rune { return c.Cipher.Text }
 func (c *Shift) GetErrors() []error { return c.Cipher.Errors }
-func (c *Shift) Encrypt() { c.Cipher.Text = shift(c.Cipher.Text, c.Cipher.Key.Shift) }
-func (c *Shift) Decrypt() { c.Cipher.Text = shift(c.Cipher.Text, -c.Cipher.Key.Shift) }
-func (c *Shift) Verify() bool { return true }
+func (c *Shift) SetText(text []rune) { c.Cipher.Text = text }
+func (c *Shift) Encrypt() { c.Cipher.crypt(pureShift, true) }
+func (c *Shift) Decrypt() { c.Cipher.crypt(pureShift, false) }
+func (c *Shift) EncryptE() error { return c.Cipher.cryptE(verifyShift, nil, true, c.Encrypt) }
+func (c *Shift) DecryptE() error { return c.Cipher.cryptE(verifyShift, nil, false, c.Decrypt) }
+func (c *Shift) Verify() bool { return c.Cipher.verify(verifyShift) }
+
+func verifyShift(k *KeyShift) []error { return nil }
 
 func shift(text []rune, shift int) []rune {
+	result := make([]rune, len(text))
 	rshift := rune(shift)
 
 	for i, r := range text {
-		text[i] = r + rshift
+		result[i] = r + rshift
 	}
 
-	return text
+	return result
 }
 
 func NewKeyShiftAlphabet(alphabet []rune, shift int) *KeyShiftAlphabet { return &KeyShiftAlphabet{Alphabet: alphabet, Shift: shift} }
 func NewShiftAlphabet(text []rune, key *KeyShiftAlphabet) *ShiftAlphabet { return &ShiftAlphabet{Cipher: &CipherClassical[KeyShiftAlphabet]{Text: text, Key: key}} }
+func EncryptShiftAlphabet(key *KeyShiftAlphabet, text []rune) ([]rune, error) { return cryptPure(verifyShiftAlphabet, checkShiftAlphabet, pureShiftAlphabet, key, text, true) }
+func DecryptShiftAlphabet(key *KeyShiftAlphabet, text []rune) ([]rune, error) { return cryptPure(verifyShiftAlphabet, checkShiftAlphabet, pureShiftAlphabet, key, text, false) }
+
+func pureShiftAlphabet(k *KeyShiftAlphabet, text []rune, encrypt bool, _ *Random) ([]rune, error) {
+	if encrypt {
+		return shiftAlphabet(text, k.Alphabet, k.Shift), nil
+	}
+
+	return shiftAlphabet(text, k.Alphabet, -k.Shift), nil
+}
 
 type KeyShiftAlphabet struct { 
 	Alphabet []rune
@@ -66,9 +121,16 @@ type KeyShiftAlphabet struct {
 type ShiftAlphabet struct { Cipher *CipherClassical[KeyShiftAlphabet] }
 func (c *ShiftAlphabet) GetText() []rune { return c.Cipher.Text }
 func (c *ShiftAlphabet) GetErrors() []error { return c.Cipher.Errors }
-func (c *ShiftAlphabet) Encrypt() { c.Cipher.Text = shiftAlphabet(c.Cipher.Text, c.Cipher.Key.Alphabet, c.Cipher.Key.Shift) }
-func (c *ShiftAlphabet) Decrypt() { c.Cipher.Text = shiftAlphabet(c.Cipher.Text, c.Cipher.Key.Alphabet, -c.Cipher.Key.Shift) }
-func (c *ShiftAlphabet) Verify() bool { return true }
+func (c *ShiftAlphabet) SetText(text []rune) { c.Cipher.Text = text }
+func (c *ShiftAlphabet) Encrypt() { c.Cipher.apply(c.Cipher.Key.Alphabet, false, c.Cipher.pure(pureShiftAlphabet, true)) }
+func (c *ShiftAlphabet) Decrypt() { c.Cipher.apply(c.Cipher.Key.Alphabet, false, c.Cipher.pure(pureShiftAlphabet, false)) }
+func (c *ShiftAlphabet) EncryptE() error { return c.Cipher.cryptE(verifyShiftAlphabet, checkShiftAlphabet, true, c.Encrypt) }
+func (c *ShiftAlphabet) DecryptE() error { return c.Cipher.cryptE(verifyShiftAlphabet, checkShiftAlphabet, false, c.Decrypt) }
+func (c *ShiftAlphabet) Verify() bool { return c.Cipher.verify(verifyShiftAlphabet) }
+
+func verifyShiftAlphabet(k *KeyShiftAlphabet) []error { return collectErrors(verifyAlphabet("alphabet", k.Alphabet)) }
+
+func checkShiftAlphabet(k *KeyShiftAlphabet, text []rune, encrypt bool) error { return checkAlphabet(text, k.Alphabet) }
 
 func shiftAlphabet(text, alphabet []rune, shift int) []rune {
 	result := make([]rune, len(text))
@@ -83,6 +145,16 @@ func shiftAlphabet(text, alphabet []rune, shift int) []rune {
 
 func NewKeyCaesar(shift int) *KeyCaesar { return &KeyCaesar{Shift: shift} }
 func NewCaesar(text []rune, key *KeyCaesar) *Caesar { return &Caesar{Cipher: &CipherClassical[KeyCaesar]{Text: text, Key: key}} }
+func EncryptCaesar(key *KeyCaesar, text []rune) ([]rune, error) { return cryptPure(verifyCaesar, nil, pureCaesar, key, text, true) }
+func DecryptCaesar(key *KeyCaesar, text []rune) ([]rune, error) { return cryptPure(verifyCaesar, nil, pureCaesar, key, text, false) }
+
+func pureCaesar(k *KeyCaesar, text []rune, encrypt bool, _ *Random) ([]rune, error) {
+	if encrypt {
+		return shiftCaesar(text, k.Shift), nil
+	}
+
+	return shiftCaesar(text, -k.Shift), nil
+}
 
 type KeyCaesar struct { 
 	Shift int 
@@ -91,35 +163,54 @@ type KeyCaesar struct {
 type Caesar struct { Cipher *CipherClassical[KeyCaesar] }
 func (c *Caesar) GetText() []rune { return c.Cipher.Text }
 func (c *Caesar) GetErrors() []error { return c.Cipher.Errors }
-func (c *Caesar) Encrypt() { c.Cipher.Text = shiftCaesar(c.Cipher.Text, c.Cipher.Key.Shift) }
-func (c *Caesar) Decrypt() { c.Cipher.Text = shiftCaesar(c.Cipher.Text, -c.Cipher.Key.Shift) }
-func (c *Caesar) Verify() bool { return true }
+func (c *Caesar) SetText(text []rune) { c.Cipher.Text = text }
+func (c *Caesar) Encrypt() { c.Cipher.crypt(pureCaesar, true) }
+func (c *Caesar) Decrypt() { c.Cipher.crypt(pureCaesar, false) }
+func (c *Caesar) EncryptE() error { return c.Cipher.cryptE(verifyCaesar, nil, true, c.Encrypt) }
+func (c *Caesar) DecryptE() error { return c.Cipher.cryptE(verifyCaesar, nil, false, c.Decrypt) }
+func (c *Caesar) Verify() bool { return c.Cipher.verify(verifyCaesar) }
+
+func verifyCaesar(k *KeyCaesar) []error { return nil }
 
 func shiftCaesar(text []rune, shift int) []rune {
+	result := make([]rune, len(text))
 	shift = utils.Mod(shift, 26)
 
 	for i, r := range text {
 		if unicode.IsUpper(r) {
-			text[i] = (r + rune(shift) - 'A') % 26 + 'A'
+			result[i] = (r + rune(shift) - 'A') % 26 + 'A'
 		} else if unicode.IsLower(r) {
-			text[i] = (r + rune(shift) - 'a') % 26 + 'a'
+			result[i] = (r + rune(shift) - 'a') % 26 + 'a'
+		} else {
+			result[i] = r
 		}
 	}
 
-	return text
+	return result
 }
 
 func NewROT13(text []rune) *ROT13 { return &ROT13{Cipher: &CipherClassical[KeyNone]{Text: text, Key: &KeyNone{}}} }
+func EncryptROT13(text []rune) ([]rune, error) { return cryptPureNone(pureROT13, text, true) }
+func DecryptROT13(text []rune) ([]rune, error) { return cryptPureNone(pureROT13, text, false) }
+
+func pureROT13(_ *KeyNone, text []rune, encrypt bool, _ *Random) ([]rune, error) { return shiftCaesar(text, 13), nil }
 
 type ROT13 struct { Cipher *CipherClassical[KeyNone] }
 func (c *ROT13) GetText() []rune { return c.Cipher.Text }
 func (c *ROT13) GetErrors() []error { return c.Cipher.Errors }
-func (c *ROT13) Encrypt() { c.Cipher.Text = shiftCaesar(c.Cipher.Text, 13) }
-func (c *ROT13) Decrypt() { c.Cipher.Text = shiftCaesar(c.Cipher.Text, 13) }
+func (c *ROT13) SetText(text []rune) { c.Cipher.Text = text }
+func (c *ROT13) Encrypt() { c.Cipher.crypt(pureROT13, true) }
+func (c *ROT13) Decrypt() { c.Cipher.crypt(pureROT13, false) }
+func (c *ROT13) EncryptE() error { c.Encrypt(); return nil }
+func (c *ROT13) DecryptE() error { c.Decrypt(); return nil }
 func (c *ROT13) Verify() bool { return true }
 
 func NewKeyAffine(alphabet []rune, a, b int) *KeyAffine { return &KeyAffine{Alphabet: alphabet, A: a, B: b} }
 func NewAffine(text []rune, key *KeyAffine) *Affine { return &Affine{Cipher: &CipherClassical[KeyAffine]{Text: text, Key: key}} }
+func EncryptAffine(key *KeyAffine, text []rune) ([]rune, error) { return cryptPure(verifyAffine, checkAffine, pureAffine, key, text, true) }
+func DecryptAffine(key *KeyAffine, text []rune) ([]rune, error) { return cryptPure(verifyAffine, checkAffine, pureAffine, key, text, false) }
+
+func pureAffine(k *KeyAffine, text []rune, encrypt bool, _ *Random) ([]rune, error) { return cryptAffine(text, k.Alphabet, k.A, k.B, encrypt), nil }
 
 type KeyAffine struct {
 	Alphabet []rune
@@ -130,9 +221,21 @@ type KeyAffine struct {
 type Affine struct { Cipher *CipherClassical[KeyAffine] }
 func (c *Affine) GetText() []rune { return c.Cipher.Text }
 func (c *Affine) GetErrors() []error { return c.Cipher.Errors }
-func (c *Affine) Encrypt() { c.Cipher.Text = cryptAffine(c.Cipher.Text, c.Cipher.Key.Alphabet, c.Cipher.Key.A, c.Cipher.Key.B, true) }
-func (c *Affine) Decrypt() { c.Cipher.Text = cryptAffine(c.Cipher.Text, c.Cipher.Key.Alphabet, c.Cipher.Key.A, c.Cipher.Key.B, false) }
-func (c *Affine) Verify() bool { return true }
+func (c *Affine) SetText(text []rune) { c.Cipher.Text = text }
+func (c *Affine) Encrypt() { c.Cipher.apply(c.Cipher.Key.Alphabet, false, c.Cipher.pure(pureAffine, true)) }
+func (c *Affine) Decrypt() { c.Cipher.apply(c.Cipher.Key.Alphabet, false, c.Cipher.pure(pureAffine, false)) }
+func (c *Affine) EncryptE() error { return c.Cipher.cryptE(verifyAffine, checkAffine, true, c.Encrypt) }
+func (c *Affine) DecryptE() error { return c.Cipher.cryptE(verifyAffine, checkAffine, false, c.Decrypt) }
+func (c *Affine) Verify() bool { return c.Cipher.verify(verifyAffine) }
+
+func verifyAffine(k *KeyAffine) []error {
+	return collectErrors(
+		verifyAlphabet("alphabet", k.Alphabet),
+		verifyCoprime("A", k.A, len(k.Alphabet)),
+	)
+}
+
+func checkAffine(k *KeyAffine, text []rune, encrypt bool) error { return checkAlphabet(text, k.Alphabet) }
 
 func cryptAffine(text, alphabet []rune, a, b int, encrypt bool) []rune {
 	result := make([]rune, len(text))
@@ -151,6 +254,10 @@ func cryptAffine(text, alphabet []rune, a, b int, encrypt bool) []rune {
 
 func NewKeyAtbash(alphabet []rune) *KeyAtbash { return &KeyAtbash{Alphabet: alphabet} }
 func NewAtbash(text []rune, key *KeyAtbash) *Atbash { return &Atbash{Cipher: &CipherClassical[KeyAtbash]{Text: text, Key: key}} }
+func EncryptAtbash(key *KeyAtbash, text []rune) ([]rune, error) { return cryptPure(verifyAtbash, checkAtbash, pureAtbash, key, text, true) }
+func DecryptAtbash(key *KeyAtbash, text []rune) ([]rune, error) { return cryptPure(verifyAtbash, checkAtbash, pureAtbash, key, text, false) }
+
+func pureAtbash(k *KeyAtbash, text []rune, encrypt bool, _ *Random) ([]rune, error) { return cryptAffine(text, k.Alphabet, -1, -1, true), nil }
 
 type KeyAtbash struct {
 	Alphabet []rune
@@ -159,12 +266,29 @@ type KeyAtbash struct {
 type Atbash struct { Cipher *CipherClassical[KeyAtbash] }
 func (c *Atbash) GetText() []rune { return c.Cipher.Text }
 func (c *Atbash) GetErrors() []error { return c.Cipher.Errors }
-func (c *Atbash) Encrypt() { c.Cipher.Text = cryptAffine(c.Cipher.Text, c.Cipher.Key.Alphabet, -1, -1, true) }
-func (c *Atbash) Decrypt() { c.Cipher.Text = cryptAffine(c.Cipher.Text, c.Cipher.Key.Alphabet, -1, -1, true) }
-func (c *Atbash) Verify() bool { return true }
+func (c *Atbash) SetText(text []rune) { c.Cipher.Text = text }
+func (c *Atbash) Encrypt() { c.Cipher.apply(c.Cipher.Key.Alphabet, false, c.Cipher.pure(pureAtbash, true)) }
+func (c *Atbash) Decrypt() { c.Cipher.apply(c.Cipher.Key.Alphabet, false, c.Cipher.pure(pureAtbash, false)) }
+func (c *Atbash) EncryptE() error { return c.Cipher.cryptE(verifyAtbash, checkAtbash, true, c.Encrypt) }
+func (c *Atbash) DecryptE() error { return c.Cipher.cryptE(verifyAtbash, checkAtbash, false, c.Decrypt) }
+func (c *Atbash) Verify() bool { return c.Cipher.verify(verifyAtbash) }
+
+func verifyAtbash(k *KeyAtbash) []error { return collectErrors(verifyAlphabet("alphabet", k.Alphabet)) }
+
+func checkAtbash(k *KeyAtbash, text []rune, encrypt bool) error { return checkAlphabet(text, k.Alphabet) }
 
 func NewKeyChaocipher(left, right []rune) *KeyChaocipher { return &KeyChaocipher{Left: left, Right: right} }
 func NewChaocipher(text []rune, key *KeyChaocipher) *Chaocipher { return &Chaocipher{Cipher: &CipherClassical[KeyChaocipher]{Text: text, Key: key}} }
+func EncryptChaocipher(key *KeyChaocipher, text []rune) ([]rune, error) { return cryptPure(verifyChaocipher, checkChaocipher, pureChaocipher, key, text, true) }
+func DecryptChaocipher(key *KeyChaocipher, text []rune) ([]rune, error) { return cryptPure(verifyChaocipher, checkChaocipher, pureChaocipher, key, text, false) }
+
+func pureChaocipher(k *KeyChaocipher, text []rune, encrypt bool, _ *Random) ([]rune, error) {
+	if err := checkChaocipher(k, text, encrypt); err != nil {
+		return nil, err
+	}
+
+	return cryptChaocipher(text, k.Left, k.Right, encrypt), nil
+}
 
 type KeyChaocipher struct { 
 	Left []rune
@@ -174,14 +298,32 @@ type KeyChaocipher struct {
 type Chaocipher struct { Cipher *CipherClassical[KeyChaocipher] }
 func (c *Chaocipher) GetText() []rune { return c.Cipher.Text }
 func (c *Chaocipher) GetErrors() []error { return c.Cipher.Errors }
-func (c *Chaocipher) Encrypt() { c.Cipher.Text = cryptChaocipher(c.Cipher.Text, c.Cipher.Key.Left, c.Cipher.Key.Right, true) }
-func (c *Chaocipher) Decrypt() { c.Cipher.Text = cryptChaocipher(c.Cipher.Text, c.Cipher.Key.Left, c.Cipher.Key.Right, false) }
-func (c *Chaocipher) Verify() bool { return true }
+func (c *Chaocipher) SetText(text []rune) { c.Cipher.Text = text }
+func (c *Chaocipher) Encrypt() { c.Cipher.apply(c.Cipher.Key.Right, false, c.Cipher.pure(pureChaocipher, true)) }
+func (c *Chaocipher) Decrypt() { c.Cipher.apply(c.Cipher.Key.Left, false, c.Cipher.pure(pureChaocipher, false)) }
+func (c *Chaocipher) EncryptE() error { return c.Cipher.cryptE(verifyChaocipher, checkChaocipher, true, c.Encrypt) }
+func (c *Chaocipher) DecryptE() error { return c.Cipher.cryptE(verifyChaocipher, checkChaocipher, false, c.Decrypt) }
+func (c *Chaocipher) Verify() bool { return c.Cipher.verify(verifyChaocipher) }
+
+func verifyChaocipher(k *KeyChaocipher) []error {
+	return collectErrors(
+		verifyAlphabet("left alphabet", k.Left),
+		verifyAlphabet("right alphabet", k.Right),
+		verifySameLength("left alphabet", k.Left, "right alphabet", k.Right),
+		verifyMin("alphabet length", len(k.Left), 3),
+	)
+}
+
+func checkChaocipher(k *KeyChaocipher, text []rune, encrypt bool) error {
+	if encrypt {
+		return checkAlphabet(text, k.Right)
+	}
+
+	return checkAlphabet(text, k.Left)
+}
 
 func cryptChaocipher(text, left, right []rune, encrypt bool) []rune {
 	result := make([]rune, len(text))
-	nadir := len(left) / 2
-	var temp rune
 
 	for i, r := range text {
 		var index int
@@ -194,20 +336,28 @@ func cryptChaocipher(text, left, right []rune, encrypt bool) []rune {
 			result[i] = right[index]
 		}
 
-		left = shiftAlphabet(left, left, index)
-		temp = left[1]
-		for j := 2; j <= nadir; j++ {
-			left[j - 1] = left[j]
-		}
-		left[nadir] = temp
-
-		right = shiftAlphabet(right, right, index + 1)
-		temp = right[2]
-		for j := 3; j <= nadir; j++ {
-			right[j - 1] = right[j]
-		}
-		right[nadir] = temp
+		left, right = permuteChaocipher(left, right, index)
 	}
 
 	return result
 }
+
+func permuteChaocipher(left, right []rune, index int) ([]rune, []rune) {
+	nadir := len(left) / 2
+
+	left = shiftAlphabet(left, left, index)
+	temp := left[1]
+	for j := 2; j <= nadir; j++ {
+		left[j - 1] = left[j]
+	}
+	left[nadir] = temp
+
+	right = shiftAlphabet(right, right, index + 1)
+	temp = right[2]
+	for j := 3; j <= nadir; j++ {
+		right[j - 1] = right[j]
+	}
+	right[nadir] = temp
+
+	return left, right
+}
@@ -43,26 +43,44 @@ var ROUTE_BLR = route{bottomleft, right, c_clockwise}
 var ROUTE_BLU = route{bottomleft, up, clockwise}
 var ROUTE_BRL = route{bottomright, left, clockwise}
 var ROUTE_BRU = route{bottomright, up, c_clockwise}
+var routes = []route{ROUTE_TLR, ROUTE_TLD, ROUTE_TRL, ROUTE_TRD, ROUTE_BLR, ROUTE_BLU, ROUTE_BRL, ROUTE_BRU}
+var routeNames = map[string]route{
+	"TLR": ROUTE_TLR, "TLD": ROUTE_TLD, "TRL": ROUTE_TRL, "TRD": ROUTE_TRD,
+	"BLR": ROUTE_BLR, "BLU": ROUTE_BLU, "BRL": ROUTE_BRL, "BRU": ROUTE_BRU,
+}
 
 func NewReverse(text []rune) *Reverse { return &Reverse{Cipher: &CipherClassical[KeyNone]{Text: text, Key: &KeyNone{}}} }
+func EncryptReverse(text []rune) ([]rune, error) { return cryptPureNone(pureReverse, text, true) }
+func DecryptReverse(text []rune) ([]rune, error) { return cryptPureNone(pureReverse, text, false) }
+
+func pureReverse(_ *KeyNone, text []rune, encrypt bool, _ *Random) ([]rune, error) { return reverse(text), nil }
 
 type Reverse struct { Cipher *CipherClassical[KeyNone] }
 func (c *Reverse) GetText() []rune { return c.Cipher.Text }
 func (c *Reverse) GetErrors() []error { return c.Cipher.Errors }
-func (c *Reverse) Encrypt() { c.Cipher.Text = reverse(c.Cipher.Text) }
-func (c *Reverse) Decrypt() { c.Cipher.Text = reverse(c.Cipher.Text) }
+func (c *Reverse) SetText(text []rune) { c.Cipher.Text = text }
+func (c *Reverse) Encrypt() { c.Cipher.crypt(pureReverse, true) }
+func (c *Reverse) Decrypt() { c.Cipher.crypt(pureReverse, false) }
+func (c *Reverse) EncryptE() error { c.Encrypt(); return nil }
+func (c *Reverse) DecryptE() error { c.Decrypt(); return nil }
 func (c *Reverse) Verify() bool { return true }
 
 func reverse(text []rune) []rune {
-	for i, j := 0, len(text) - 1; i < j; i, j = i + 1, j - 1 {
-		text[i], text[j] = text[j], text[i]
+	result := make([]rune, len(text))
+
+	for i, r := range text {
+		result[len(text) - i - 1] = r
 	}
 
-	return text
+	return result
 }
 
 func NewKeyZigzag(lines int) *KeyZigzag { return &KeyZigzag{Lines: lines} }
 func NewZigzag(text []rune, key *KeyZigzag) *Zigzag { return &Zigzag{Cipher: &CipherClassical[KeyZigzag]{Text: text, Key: key}} }
+func EncryptZigzag(key *KeyZigzag, text []rune) ([]rune, error) { return cryptPure(verifyZigzag, nil, pureZigzag, key, text, true) }
+func DecryptZigzag(key *KeyZigzag, text []rune) ([]rune, error) { return cryptPure(verifyZigzag, nil, pureZigzag, key, text, false) }
+
+func pureZigzag(k *KeyZigzag, text []rune, encrypt bool, _ *Random) ([]rune, error) { return cryptZigzag(text, k.Lines, encrypt), nil }
 
 type KeyZigzag struct { 
 	Lines int 
@@ -71,9 +89,14 @@ type KeyZigzag struct {
 type Zigzag struct { Cipher *CipherClassical[KeyZigzag] }
 func (c *Zigzag) GetText() []rune { return c.Cipher.Text }
 func (c *Zigzag) GetErrors() []error { return c.Cipher.Errors }
-func (c *Zigzag) Encrypt() { c.Cipher.Text = cryptZigzag(c.Cipher.Text, c.Cipher.Key.Lines, true) }
-func (c *Zigzag) Decrypt() { c.Cipher.Text = cryptZigzag(c.Cipher.Text, c.Cipher.Key.Lines, false) }
-func (c *Zigzag) Verify() bool { return true }
+func (c *Zigzag) SetText(text []rune) { c.Cipher.Text = text }
+func (c *Zigzag) Encrypt() { c.Cipher.crypt(pureZigzag, true) }
+func (c *Zigzag) Decrypt() { c.Cipher.crypt(pureZigzag, false) }
+func (c *Zigzag) EncryptE() error { return c.Cipher.cryptE(verifyZigzag, nil, true, c.Encrypt) }
+func (c *Zigzag) DecryptE() error { return c.Cipher.cryptE(verifyZigzag, nil, false, c.Decrypt) }
+func (c *Zigzag) Verify() bool { return c.Cipher.verify(verifyZigzag) }
+
+func verifyZigzag(k *KeyZigzag) []error { return collectErrors(verifyMin("lines", k.Lines, 1)) }
 
 func cryptZigzag(text []rune, lines int, encrypt bool) []rune {
 	if lines < 2 {
@@ -113,6 +136,10 @@ func cryptZigzag(text []rune, lines int, encrypt bool) []rune {
 
 func NewKeyScytale(lines int) *KeyScytale { return &KeyScytale{Lines: lines} }
 func NewScytale(text []rune, key *KeyScytale) *Scytale { return &Scytale{Cipher: &CipherClassical[KeyScytale]{Text: text, Key: key}} }
+func EncryptScytale(key *KeyScytale, text []rune) ([]rune, error) { return cryptPure(verifyScytale, nil, pureScytale, key, text, true) }
+func DecryptScytale(key *KeyScytale, text []rune) ([]rune, error) { return cryptPure(verifyScytale, nil, pureScytale, key, text, false) }
+
+func pureScytale(k *KeyScytale, text []rune, encrypt bool, _ *Random) ([]rune, error) { return cryptScytale(text, k.Lines, encrypt), nil }
 
 type KeyScytale struct { 
 	Lines int
@@ -121,9 +148,14 @@ type KeyScytale struct {
 type Scytale struct { Cipher *CipherClassical[KeyScytale] }
 func (c *Scytale) GetText() []rune { return c.Cipher.Text }
 func (c *Scytale) GetErrors() []error { return c.Cipher.Errors }
-func (c *Scytale) Encrypt() { c.Cipher.Text = cryptScytale(c.Cipher.Text, c.Cipher.Key.Lines, true) }
-func (c *Scytale) Decrypt() { c.Cipher.Text = cryptScytale(c.Cipher.Text, c.Cipher.Key.Lines, false) }
-func (c *Scytale) Verify() bool { return true }
+func (c *Scytale) SetText(text []rune) { c.Cipher.Text = text }
+func (c *Scytale) Encrypt() { c.Cipher.crypt(pureScytale, true) }
+func (c *Scytale) Decrypt() { c.Cipher.crypt(pureScytale, false) }
+func (c *Scytale) EncryptE() error { return c.Cipher.cryptE(verifyScytale, nil, true, c.Encrypt) }
+func (c *Scytale) DecryptE() error { return c.Cipher.cryptE(verifyScytale, nil, false, c.Decrypt) }
+func (c *Scytale) Verify() bool { return c.Cipher.verify(verifyScytale) }
+
+func verifyScytale(k *KeyScytale) []error { return collectErrors(verifyMin("lines", k.Lines, 1)) }
 
 func cryptScytale(text []rune, lines int, encrypt bool) []rune {
 	if lines < 2 {
@@ -149,6 +181,10 @@ func cryptScytale(text []rune, lines int, encrypt bool) []rune {
 
 func NewKeyRoute(width int, r route) *KeyRoute { return &KeyRoute{Width: width, Route: r} }
 func NewRouteSpiral(text []rune, key *KeyRoute) *RouteSpiral { return &RouteSpiral{Cipher: &CipherClassical[KeyRoute]{Text: text, Key: key}} }
+func EncryptRouteSpiral(key *KeyRoute, text []rune) ([]rune, error) { return cryptPure(verifyRoute, nil, pureRouteSpiral, key, text, true) }
+func DecryptRouteSpiral(key *KeyRoute, text []rune) ([]rune, error) { return cryptPure(verifyRoute, nil, pureRouteSpiral, key, text, false) }
+
+func pureRouteSpiral(k *KeyRoute, text []rune, encrypt bool, _ *Random) ([]rune, error) { return cryptRoute(text, k.Width, k.Route, routeTypeSpiral, encrypt), nil }
 
 type KeyRoute struct { 
 	Width int 
@@ -158,20 +194,40 @@ type KeyRoute struct {
 type RouteSpiral struct { Cipher *CipherClassical[KeyRoute] }
 func (c *RouteSpiral) GetText() []rune { return c.Cipher.Text }
 func (c *RouteSpiral) GetErrors() []error { return c.Cipher.Errors }
-func (c *RouteSpiral) Encrypt() { c.Cipher.Text = cryptRoute(c.Cipher.Text, c.Cipher.Key.Width, c.Cipher.Key.Route, routeTypeSpiral, true); }
-func (c *RouteSpiral) Decrypt() { c.Cipher.Text = cryptRoute(c.Cipher.Text, c.Cipher.Key.Width, c.Cipher.Key.Route, routeTypeSpiral, false); }
-func (c *RouteSpiral) Verify() bool { return true }
+func (c *RouteSpiral) SetText(text []rune) { c.Cipher.Text = text }
+func (c *RouteSpiral) Encrypt() { c.Cipher.crypt(pureRouteSpiral, true) }
+func (c *RouteSpiral) Decrypt() { c.Cipher.crypt(pureRouteSpiral, false) }
+func (c *RouteSpiral) EncryptE() error { return c.Cipher.cryptE(verifyRoute, nil, true, c.Encrypt) }
+func (c *RouteSpiral) DecryptE() error { return c.Cipher.cryptE(verifyRoute, nil, false, c.Decrypt) }
+func (c *RouteSpiral) Verify() bool { return c.Cipher.verify(verifyRoute) }
+
+func verifyRoute(k *KeyRoute) []error {
+	errs := collectErrors(verifyMin("width", k.Width, 1))
+
+	if !utils.Contains(routes, k.Route) {
+		errs = append(errs, keyError("unknown route %v", k.Route))
+	}
+
+	return errs
+}
 
 func NewRouteSerpent(text []rune, key *KeyRoute) *RouteSerpent {
 	return &RouteSerpent{Cipher: &CipherClassical[KeyRoute]{Text: text, Key: key}}
 }
+func EncryptRouteSerpent(key *KeyRoute, text []rune) ([]rune, error) { return cryptPure(verifyRoute, nil, pureRouteSerpent, key, text, true) }
+func DecryptRouteSerpent(key *KeyRoute, text []rune) ([]rune, error) { return cryptPure(verifyRoute, nil, pureRouteSerpent, key, text, false) }
+
+func pureRouteSerpent(k *KeyRoute, text []rune, encrypt bool, _ *Random) ([]rune, error) { return cryptRoute(text, k.Width, k.Route, routeTypeSerpent, encrypt), nil }
 
 type RouteSerpent struct { Cipher *CipherClassical[KeyRoute] }
 func (c *RouteSerpent) GetText() []rune { return c.Cipher.Text }
 func (c *RouteSerpent) GetErrors() []error { return c.Cipher.Errors }
-func (c *RouteSerpent) Encrypt() { c.Cipher.Text = cryptRoute(c.Cipher.Text, c.Cipher.Key.Width, c.Cipher.Key.Route, routeTypeSerpent, true) }
-func (c *RouteSerpent) Decrypt() { c.Cipher.Text = cryptRoute(c.Cipher.Text, c.Cipher.Key.Width, c.Cipher.Key.Route, routeTypeSerpent, false) }
-func (c *RouteSerpent) Verify() bool { return true }
+func (c *RouteSerpent) SetText(text []rune) { c.Cipher.Text = text }
+func (c *RouteSerpent) Encrypt() { c.Cipher.crypt(pureRouteSerpent, true) }
+func (c *RouteSerpent) Decrypt() { c.Cipher.crypt(pureRouteSerpent, false) }
+func (c *RouteSerpent) EncryptE() error { return c.Cipher.cryptE(verifyRoute, nil, true, c.Encrypt) }
+func (c *RouteSerpent) DecryptE() error { return c.Cipher.cryptE(verifyRoute, nil, false, c.Decrypt) }
+func (c *RouteSerpent) Verify() bool { return c.Cipher.verify(verifyRoute) }
 
 func cryptRoute(text []rune, width int, r route, rt routeType, encrypt bool) []rune {
 	result := make([]rune, len(text))
@@ -256,12 +312,19 @@ func cryptRoute(text []rune, width int, r route, rt routeType, encrypt bool) []r
 }
 
 func NewMagnet(text []rune) *Magnet { return &Magnet{Cipher: &CipherClassical[KeyNone]{Text: text, Key: &KeyNone{}}} }
+func EncryptMagnet(text []rune) ([]rune, error) { return cryptPureNone(pureMagnet, text, true) }
+func DecryptMagnet(text []rune) ([]rune, error) { return cryptPureNone(pureMagnet, text, false) }
+
+func pureMagnet(_ *KeyNone, text []rune, encrypt bool, _ *Random) ([]rune, error) { return cryptMagnet(text, encrypt), nil }
 
 type Magnet struct { Cipher *CipherClassical[KeyNone] }
 func (c *Magnet) GetText() []rune { return c.Cipher.Text }
 func (c *Magnet) GetErrors() []error { return c.Cipher.Errors }
-func (c *Magnet) Encrypt() { c.Cipher.Text = cryptMagnet(c.Cipher.Text, true) }
-func (c *Magnet) Decrypt() { c.Cipher.Text = cryptMagnet(c.Cipher.Text, false) }
+func (c *Magnet) SetText(text []rune) { c.Cipher.Text = text }
+func (c *Magnet) Encrypt() { c.Cipher.crypt(pureMagnet, true) }
+func (c *Magnet) Decrypt() { c.Cipher.crypt(pureMagnet, false) }
+func (c *Magnet) EncryptE() error { c.Encrypt(); return nil }
+func (c *Magnet) DecryptE() error { c.Decrypt(); return nil }
 func (c *Magnet) Verify() bool { return true }
 
 func cryptMagnet(text []rune, encrypt bool) []rune {
@@ -286,12 +349,19 @@ func cryptMagnet(text []rune, encrypt bool) []rune {
 }
 
 func NewElastic(text []rune) *Elastic { return &Elastic{Cipher: &CipherClassical[KeyNone]{Text: text, Key: &KeyNone{}}} }
+func EncryptElastic(text []rune) ([]rune, error) { return cryptPureNone(pureElastic, text, true) }
+func DecryptElastic(text []rune) ([]rune, error) { return cryptPureNone(pureElastic, text, false) }
+
+func pureElastic(_ *KeyNone, text []rune, encrypt bool, _ *Random) ([]rune, error) { return cryptElastic(text, encrypt), nil }
 
 type Elastic struct { Cipher *CipherClassical[KeyNone] }
 func (c *Elastic) GetText() []rune { return c.Cipher.Text }
 func (c *Elastic) GetErrors() []error { return c.Cipher.Errors }
-func (c *Elastic) Encrypt() { c.Cipher.Text = cryptElastic(c.Cipher.Text, true) }
-func (c *Elastic) Decrypt() { c.Cipher.Text = cryptElastic(c.Cipher.Text, false) }
+func (c *Elastic) SetText(text []rune) { c.Cipher.Text = text }
+func (c *Elastic) Encrypt() { c.Cipher.crypt(pureElastic, true) }
+func (c *Elastic) Decrypt() { c.Cipher.crypt(pureElastic, false) }
+func (c *Elastic) EncryptE() error { c.Encrypt(); return nil }
+func (c *Elastic) DecryptE() error { c.Decrypt(); return nil }
 func (c *Elastic) Verify() bool { return true }
 
 func cryptElastic(text []rune, encrypt bool) []rune {
@@ -3,24 +3,24 @@ package classical
 import (
 	"cryptochev/utils"
 	"math"
-	"math/rand"
 	"unicode"
 )
 
 const AlphabetL = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
 const AlphabetL25 = "ABCDEFGHIKLMNOPQRSTUVWXYZ"
 const AlphabetL36 = "ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890"
+const AlphabetL27 = "ABCDEFGHIJKLMNOPQRSTUVWXYZ+"
 
 func RandomAlphabetL() []rune {
-	return utils.Shuffle([]rune(AlphabetL))
+	return defaultRandom.AlphabetL()
 }
 
 func RandomAlphabetL25() []rune {
-	return utils.Shuffle([]rune(AlphabetL25))
+	return defaultRandom.AlphabetL25()
 }
 
 func RandomAlphabetL36() []rune {
-	return utils.Shuffle([]rune(AlphabetL36))
+	return defaultRandom.AlphabetL36()
 }
 
 func Alphabet26Coprimes() []int {
@@ -57,8 +57,7 @@ func AlphabetKey(alphabet, key []rune) []rune {
 }
 
 func RandomAlphabetKey(alphabet, key []rune) []rune {
-	ukey, remains := buildAlphabetKey(alphabet, key)
-	return append(ukey, utils.Shuffle(remains)...)
+	return defaultRandom.AlphabetKey(alphabet, key)
 }
 
 func AlphabetKeyL25(key []rune) []rune {
@@ -69,6 +68,11 @@ func AlphabetKeyL36(key []rune) []rune {
 	return AlphabetKey([]rune(AlphabetL36), key)
 }
 
+// The filler takes the place of '+' as the 27th rune of the cube.
+func AlphabetKeyL27(key []rune, filler rune) []rune {
+	return AlphabetKey(append([]rune(AlphabetL), filler), key)
+}
+
 func RandomAlphabetKeyL25(key []rune) []rune {
 	return RandomAlphabetKey([]rune(AlphabetL25), key)
 }
@@ -77,27 +81,20 @@ func RandomAlphabetKeyL36(key []rune) []rune {
 	return RandomAlphabetKey([]rune(AlphabetL36), key)
 }
 
+func RandomAlphabetKeyL27(key []rune, filler rune) []rune {
+	return RandomAlphabetKey(append([]rune(AlphabetL), filler), key)
+}
+
 func RandomLetter() rune {
-	return rune(65 + rand.Intn(26))
+	return defaultRandom.Letter()
 }
 
 func RandomRuneFrom(r []rune) rune {
-	return r[rand.Intn(len(r))]
+	return defaultRandom.RuneFrom(r)
 }
 
 func ToPadded(rs []rune, width int) []rune {
-	rpad := make([]rune, 0, width)
-
-	if len(rs) % width != 0 {
-		pad := width - len(rs) % width
-		utils.SeedRand()
-
-		for i := 0; i < pad; i++ {
-			rpad = append(rpad, RandomRuneFrom(rs))
-		}
-	}
-
-	return append(rs, rpad...)
+	return defaultRandom.Padded(rs, width)
 }
 
 func ToUnpadded(s string, width int) string {
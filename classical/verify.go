@@ -0,0 +1,151 @@
+package classical
+
+import (
+	"cryptochev/utils"
+	"math"
+)
+
+// The errors are those of the key as it is now, the ones of an earlier verification are dropped.
+func (c *CipherClassical[K]) verify(verify func(*K) []error) bool {
+	if c.Key == nil {
+		c.Errors = []error{keyError("missing key")}
+		return false
+	}
+
+	errs := verify(c.Key)
+	c.Errors = errs
+
+	return len(errs) == 0
+}
+
+func collectErrors(errs ...error) []error {
+	result := make([]error, 0, len(errs))
+
+	for _, err := range errs {
+		if err != nil {
+			result = append(result, err)
+		}
+	}
+
+	return result
+}
+
+func verifyAlphabet(name string, alphabet []rune) error {
+	if len(alphabet) == 0 {
+		return keyError("%s is empty", name)
+	}
+
+	seen := make(map[rune]bool, len(alphabet))
+	for _, r := range alphabet {
+		if seen[r] {
+			return keyError("%s contains %q more than once", name, r)
+		}
+		seen[r] = true
+	}
+
+	return nil
+}
+
+func verifyRunesIn(name string, rs, alphabet []rune) error {
+	amap := buildIndexMap(alphabet)
+
+	for _, r := range rs {
+		if _, found := amap[r]; !found {
+			return keyError("%s contains %q which is not in the alphabet", name, r)
+		}
+	}
+
+	return nil
+}
+
+func verifyNotEmpty(name string, rs []rune) error {
+	if len(rs) == 0 {
+		return keyError("%s is empty", name)
+	}
+
+	return nil
+}
+
+func verifyMin(name string, n, min int) error {
+	if n < min {
+		return keyError("%s must be at least %d, got %d", name, min, n)
+	}
+
+	return nil
+}
+
+func verifySameLength(name1 string, a1 []rune, name2 string, a2 []rune) error {
+	if len(a1) != len(a2) {
+		return keyError("%s and %s have different lengths (%d and %d)", name1, name2, len(a1), len(a2))
+	}
+
+	return nil
+}
+
+func verifyLength(name string, rs []rune, length int) error {
+	if len(rs) != length {
+		return keyError("%s must have %d runes, got %d", name, length, len(rs))
+	}
+
+	return nil
+}
+
+func verifySquare(name string, alphabet []rune) error {
+	width := int(math.Sqrt(float64(len(alphabet))))
+
+	if width < 2 || width * width != len(alphabet) {
+		return keyError("%s length %d does not fill a square", name, len(alphabet))
+	}
+
+	return nil
+}
+
+func verifyCoprime(name string, n, m int) error {
+	if m > 0 && !utils.IsCoprime(uint(utils.Mod(n, m)), uint(m)) {
+		return keyError("%s %d is not coprime with %d", name, n, m)
+	}
+
+	return nil
+}
+
+func checkAlphabet(text, alphabet []rune) error {
+	amap := buildIndexMap(alphabet)
+
+	for i, r := range text {
+		if _, found := amap[r]; !found {
+			return &InvalidRuneError{Position: i, Rune: r}
+		}
+	}
+
+	return nil
+}
+
+func checkLength(text []rune, multiple int) error {
+	if len(text) % multiple != 0 {
+		return &InvalidLengthError{Length: len(text), Multiple: multiple}
+	}
+
+	return nil
+}
+
+func checkPairs(text, alphabet1, alphabet2 []rune) error {
+	if err := checkLength(text, 2); err != nil {
+		return err
+	}
+
+	amap1 := buildIndexMap(alphabet1)
+	amap2 := buildIndexMap(alphabet2)
+
+	for i, r := range text {
+		amap := amap1
+		if i % 2 == 1 {
+			amap = amap2
+		}
+
+		if _, found := amap[r]; !found {
+			return &InvalidRuneError{Position: i, Rune: r}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,167 @@
+package lorenz
+
+import "cryptochev/classical"
+
+// A Code holds the five impulses of an ITA2 teleprinter character, impulse 1 in the highest
+// bit. A set bit is a mark, written x at Bletchley Park, a clear bit is a space, written dot.
+type Code uint8
+
+const (
+	Null Code = 0b00000
+	CarriageReturn Code = 0b00010
+	LineFeed Code = 0b01000
+	Space Code = 0b00100
+	Figures Code = 0b11011
+	Letters Code = 0b11111
+)
+
+func (c Code) Impulse(i int) bool { return c >> (5 - i) & 1 == 1 }
+
+// Codes of A to Z in the letters shift, the figures shift prints the rune at the same index.
+var letterCodes = [26]Code{
+	0b11000, 0b10011, 0b01110, 0b10010, 0b10000, 0b10110, 0b01011, 0b00101, 0b01100, 0b11010,
+	0b11110, 0b01001, 0b00111, 0b00110, 0b00011, 0b01101, 0b11101, 0b01010, 0b10100, 0b00001,
+	0b11100, 0b01111, 0b11001, 0b10111, 0b10101, 0b10001,
+}
+
+// D, F, G and H are left to national use by ITA2, they take the US teleprinter symbols.
+var figures = []rune("-?:$3!&#8\a().,9014'57=2/6+")
+
+var shiftless = map[rune]Code{' ': Space, '\r': CarriageReturn, '\n': LineFeed}
+
+// Bletchley Park wrote the codes without a letter as digits and a slash, 5 and 8 being the
+// figures and letters shifts.
+var tapeSymbols = map[Code]rune{Null: '/', CarriageReturn: '3', LineFeed: '4', Space: '9', Figures: '5', Letters: '8'}
+
+var letterShift, figureShift = shiftTables()
+
+func shiftTables() (map[rune]Code, map[rune]Code) {
+	letters := make(map[rune]Code, 26)
+	figs := make(map[rune]Code, 26)
+	for i, c := range letterCodes {
+		letters[rune('A' + i)] = c
+		figs[figures[i]] = c
+	}
+
+	return letters, figs
+}
+
+// Encode starts in the letters shift and inserts a shift code whenever the text moves between
+// letters and figures. Space, carriage return and line feed print in both shifts.
+func Encode(text []rune) ([]Code, error) {
+	codes := make([]Code, 0, len(text))
+	figs := false
+
+	for i, r := range text {
+		if c, found := shiftless[r]; found {
+			codes = append(codes, c)
+		} else if c, found := letterShift[r]; found {
+			if figs {
+				codes = append(codes, Letters)
+				figs = false
+			}
+			codes = append(codes, c)
+		} else if c, found := figureShift[r]; found {
+			if !figs {
+				codes = append(codes, Figures)
+				figs = true
+			}
+			codes = append(codes, c)
+		} else {
+			return nil, &classical.InvalidRuneError{Position: i, Rune: r}
+		}
+	}
+
+	return codes, nil
+}
+
+// Decode starts in the letters shift, null codes print nothing.
+func Decode(codes []Code) []rune {
+	text := make([]rune, 0, len(codes))
+	figs := false
+
+	for _, c := range codes {
+		switch c {
+		case Null:
+		case Figures:
+			figs = true
+		case Letters:
+			figs = false
+		case Space:
+			text = append(text, ' ')
+		case CarriageReturn:
+			text = append(text, '\r')
+		case LineFeed:
+			text = append(text, '\n')
+		default:
+			i := letterIndex(c)
+			if figs {
+				text = append(text, figures[i])
+			} else {
+				text = append(text, rune('A' + i))
+			}
+		}
+	}
+
+	return text
+}
+
+func letterIndex(c Code) int {
+	for i, lc := range letterCodes {
+		if lc == c {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// FormatTape writes one rune per code in the Bletchley Park notation, the way the cipher tape
+// was transcribed since it holds any of the 32 codes.
+func FormatTape(codes []Code) []rune {
+	tape := make([]rune, len(codes))
+	for i, c := range codes {
+		if r, found := tapeSymbols[c]; found {
+			tape[i] = r
+		} else {
+			tape[i] = rune('A' + letterIndex(c))
+		}
+	}
+
+	return tape
+}
+
+func ParseTape(tape []rune) ([]Code, error) {
+	codes := make([]Code, len(tape))
+
+	for i, r := range tape {
+		if c, found := letterShift[r]; found {
+			codes[i] = c
+			continue
+		}
+
+		found := false
+		for c, symbol := range tapeSymbols {
+			if symbol == r {
+				codes[i] = c
+				found = true
+			}
+		}
+
+		if !found {
+			return nil, &classical.InvalidRuneError{Position: i, Rune: r}
+		}
+	}
+
+	return codes, nil
+}
+
+// String writes the impulses as crosses and dots, like the wheel patterns.
+func (c Code) String() string {
+	rs := make([]rune, 5)
+	for i := range rs {
+		rs[i] = pin(c.Impulse(i + 1))
+	}
+
+	return string(rs)
+}
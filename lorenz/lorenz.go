@@ -0,0 +1,317 @@
+package lorenz
+
+import (
+	"cryptochev/classical"
+	"fmt"
+)
+
+var ChiSizes = [5]int{41, 31, 29, 26, 23}
+var PsiSizes = [5]int{43, 47, 51, 53, 59}
+
+// The first motor wheel has 61 pins and the second 37.
+var MuSizes = [2]int{61, 37}
+
+// The limitation holds the psi wheels when the second motor wheel would move them. The SZ40 has
+// none, the SZ42A is limited by chi 2 one back and the SZ42B by chi 2 and psi 1 one back.
+type Limitation int
+
+const (
+	LimitationNone Limitation = iota
+	LimitationChi2
+	LimitationChi2Psi1
+)
+
+// Pattern sets the pins of a wheel, x for an active cam and a dot otherwise. Position is the
+// number of the pin under the reading head, counted from 1 as on the wheel.
+type Wheel struct {
+	Pattern string
+	Position int
+}
+
+// P5 adds the fifth impulse of the plain text two back to the limitation.
+type Key struct {
+	Chi [5]Wheel
+	Psi [5]Wheel
+	Mu [2]Wheel
+	Limitation Limitation
+	P5 bool
+}
+
+func NewKey(chi, psi [5]Wheel, mu [2]Wheel, limitation Limitation, p5 bool) *Key {
+	return &Key{Chi: chi, Psi: psi, Mu: mu, Limitation: limitation, P5: p5}
+}
+func New(text []rune, key *Key) *Lorenz { return &Lorenz{Text: text, Key: key} }
+func Encrypt(key *Key, text []rune) ([]rune, error) { return cryptPure(key, text, encrypt) }
+func Decrypt(key *Key, text []rune) ([]rune, error) { return cryptPure(key, text, decrypt) }
+
+// Lorenz adapts the machine to classical.ICipherClassical. The plain text is ITA2 text and the
+// cipher text is the tape in Bletchley Park notation.
+type Lorenz struct {
+	Text []rune
+	Errors []error
+	Key *Key
+}
+
+func (c *Lorenz) GetText() []rune { return c.Text }
+func (c *Lorenz) GetErrors() []error { return c.Errors }
+func (c *Lorenz) SetText(text []rune) { c.Text = text }
+func (c *Lorenz) Encrypt() { c.setText(encrypt(c.Text, c.Key)) }
+func (c *Lorenz) Decrypt() { c.setText(decrypt(c.Text, c.Key)) }
+func (c *Lorenz) EncryptE() error { return c.cryptE(c.Encrypt) }
+func (c *Lorenz) DecryptE() error { return c.cryptE(c.Decrypt) }
+
+func (c *Lorenz) Verify() bool {
+	errs := verifyKey(c.Key)
+	c.Errors = errs
+
+	return len(errs) == 0
+}
+
+func (c *Lorenz) cryptE(crypt func()) error {
+	if errs := verifyKey(c.Key); len(errs) > 0 {
+		return errs[0]
+	}
+
+	errs := len(c.Errors)
+	crypt()
+
+	if len(c.Errors) > errs {
+		err := c.Errors[errs]
+		c.Errors = c.Errors[:errs]
+		return err
+	}
+
+	return nil
+}
+
+func (c *Lorenz) setText(text []rune, err error) {
+	if err != nil {
+		c.Errors = append(c.Errors, err)
+		return
+	}
+
+	c.Text = text
+}
+
+// Crypts a copy of the text with the same functions as the struct, after verifying the key.
+func cryptPure(key *Key, text []rune, crypt func([]rune, *Key) ([]rune, error)) ([]rune, error) {
+	if errs := verifyKey(key); len(errs) > 0 {
+		return nil, errs[0]
+	}
+
+	return crypt(append([]rune{}, text...), key)
+}
+
+func keyError(format string, a ...any) error {
+	return fmt.Errorf("%w: %s", classical.ErrInvalidKey, fmt.Sprintf(format, a...))
+}
+
+func verifyKey(k *Key) []error {
+	if k == nil {
+		return []error{keyError("missing key")}
+	}
+
+	errs := make([]error, 0)
+	for i, w := range k.Chi {
+		errs = append(errs, verifyWheel(fmt.Sprintf("chi %d", i + 1), w, ChiSizes[i])...)
+	}
+	for i, w := range k.Psi {
+		errs = append(errs, verifyWheel(fmt.Sprintf("psi %d", i + 1), w, PsiSizes[i])...)
+	}
+	for i, w := range k.Mu {
+		errs = append(errs, verifyWheel(fmt.Sprintf("mu %d", MuSizes[i]), w, MuSizes[i])...)
+	}
+
+	if k.Limitation < LimitationNone || k.Limitation > LimitationChi2Psi1 {
+		errs = append(errs, keyError("unknown limitation %d", k.Limitation))
+	}
+
+	if k.P5 && k.Limitation == LimitationNone {
+		errs = append(errs, keyError("P5 limitation needs a chi 2 limitation"))
+	}
+
+	return errs
+}
+
+func verifyWheel(name string, w Wheel, size int) []error {
+	errs := make([]error, 0)
+	pattern := []rune(w.Pattern)
+
+	if len(pattern) != size {
+		errs = append(errs, keyError("%s has %d pins, got a pattern of %d", name, size, len(pattern)))
+	}
+
+	for _, r := range pattern {
+		if r != 'x' && r != '.' {
+			errs = append(errs, keyError("%s pattern contains %q, pins are x or a dot", name, r))
+			break
+		}
+	}
+
+	if w.Position < 1 || w.Position > size {
+		errs = append(errs, keyError("%s position %d is not between 1 and %d", name, w.Position, size))
+	}
+
+	return errs
+}
+
+func pin(active bool) rune {
+	if active {
+		return 'x'
+	}
+
+	return '.'
+}
+
+type wheel struct {
+	pins []bool
+	position int
+}
+
+func newWheel(w Wheel) wheel {
+	pattern := []rune(w.Pattern)
+	pins := make([]bool, len(pattern))
+	for i, r := range pattern {
+		pins[i] = r == 'x'
+	}
+
+	return wheel{pins: pins, position: w.Position - 1}
+}
+
+func (w *wheel) bit() bool { return w.pins[w.position] }
+func (w *wheel) step() { w.position = (w.position + 1) % len(w.pins) }
+
+type machine struct {
+	chi [5]wheel
+	psi [5]wheel
+	mu61 wheel
+	mu37 wheel
+	limitation Limitation
+	p5 bool
+	plain5 bool
+}
+
+func newMachine(k *Key) *machine {
+	m := &machine{mu61: newWheel(k.Mu[0]), mu37: newWheel(k.Mu[1]), limitation: k.Limitation, p5: k.P5}
+	for i := range m.chi {
+		m.chi[i] = newWheel(k.Chi[i])
+		m.psi[i] = newWheel(k.Psi[i])
+	}
+
+	return m
+}
+
+// The key is the sum of the chi and psi wheels, impulse by impulse.
+func (m *machine) key() Code {
+	var c Code
+	for i := range m.chi {
+		if m.chi[i].bit() != m.psi[i].bit() {
+			c |= 1 << (4 - i)
+		}
+	}
+
+	return c
+}
+
+// The chi wheels and mu 61 move at every character, mu 37 moves when mu 61 shows a cross and the
+// psi wheels move together when the total motor is a cross. The total motor is the pin of mu 37,
+// the basic motor, except that a dot limitation always moves the psi wheels.
+func (m *machine) step(plain Code) {
+	motor := m.mu37.bit()
+
+	if m.limitation != LimitationNone {
+		limitation := m.chi[1].bit()
+		if m.limitation == LimitationChi2Psi1 {
+			limitation = limitation != m.psi[0].bit()
+		}
+		if m.p5 {
+			limitation = limitation != m.plain5
+		}
+		motor = motor || !limitation
+	}
+	m.plain5 = plain.Impulse(5)
+
+	if motor {
+		for i := range m.psi {
+			m.psi[i].step()
+		}
+	}
+
+	if m.mu61.bit() {
+		m.mu37.step()
+	}
+	m.mu61.step()
+
+	for i := range m.chi {
+		m.chi[i].step()
+	}
+}
+
+func crypt(codes []Code, k *Key, encrypt bool) []Code {
+	m := newMachine(k)
+	result := make([]Code, len(codes))
+
+	for i, c := range codes {
+		result[i] = c ^ m.key()
+		if encrypt {
+			m.step(c)
+		} else {
+			m.step(result[i])
+		}
+	}
+
+	return result
+}
+
+// EncryptCodes and DecryptCodes only differ with a P5 limitation, which reads the plain text.
+func EncryptCodes(key *Key, codes []Code) ([]Code, error) { return cryptCodes(key, codes, true) }
+func DecryptCodes(key *Key, codes []Code) ([]Code, error) { return cryptCodes(key, codes, false) }
+
+func cryptCodes(key *Key, codes []Code, encrypt bool) ([]Code, error) {
+	if errs := verifyKey(key); len(errs) > 0 {
+		return nil, errs[0]
+	}
+
+	return crypt(codes, key, encrypt), nil
+}
+
+func encrypt(text []rune, k *Key) ([]rune, error) {
+	codes, err := Encode(text)
+	if err != nil {
+		return nil, err
+	}
+
+	return FormatTape(crypt(codes, k, true)), nil
+}
+
+func decrypt(text []rune, k *Key) ([]rune, error) {
+	codes, err := ParseTape(text)
+	if err != nil {
+		return nil, err
+	}
+
+	return Decode(crypt(codes, k, false)), nil
+}
+
+// A nil Random draws from the default source.
+func RandomKey(r *classical.Random, limitation Limitation) *Key {
+	k := &Key{Limitation: limitation}
+	for i := range k.Chi {
+		k.Chi[i] = randomWheel(r, ChiSizes[i])
+		k.Psi[i] = randomWheel(r, PsiSizes[i])
+	}
+	for i := range k.Mu {
+		k.Mu[i] = randomWheel(r, MuSizes[i])
+	}
+
+	return k
+}
+
+func randomWheel(r *classical.Random, size int) Wheel {
+	pattern := make([]rune, size)
+	for i := range pattern {
+		pattern[i] = pin(r.Intn(2) == 1)
+	}
+
+	return Wheel{Pattern: string(pattern), Position: 1 + r.Intn(size)}
+}
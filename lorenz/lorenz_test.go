@@ -0,0 +1,275 @@
+package lorenz
+
+import (
+	"cryptochev/classical"
+	"cryptochev/utils"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLorenz(t *testing.T) {
+	t.Logf("Using seed: %d\n", utils.SeedRand())
+	t.Run("TestBaudot", testBaudot)
+	t.Run("TestTape", testTape)
+	t.Run("TestWheels", testWheels)
+	t.Run("TestMotor", testMotor)
+	t.Run("TestLimitation", testLimitation)
+	t.Run("TestAdapter", testAdapter)
+	t.Run("TestVerify", testVerify)
+}
+
+// Every wheel is all dots at position 1, so the key is null until a pattern is changed.
+func dotKey() *Key {
+	k := &Key{}
+	for i := range k.Chi {
+		k.Chi[i] = Wheel{Pattern: strings.Repeat(".", ChiSizes[i]), Position: 1}
+		k.Psi[i] = Wheel{Pattern: strings.Repeat(".", PsiSizes[i]), Position: 1}
+	}
+	for i := range k.Mu {
+		k.Mu[i] = Wheel{Pattern: strings.Repeat(".", MuSizes[i]), Position: 1}
+	}
+
+	return k
+}
+
+func oneCross(size, at int) string { return strings.Repeat(".", at - 1) + "x" + strings.Repeat(".", size - at) }
+
+func testBaudot(t *testing.T) {
+	codes, err := Encode([]rune("A1 B"))
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+
+	exp := []Code{0b11000, Figures, 0b11101, Space, Letters, 0b10011}
+	if len(codes) != len(exp) {
+		t.Fatalf("Encode A1 B gave %v, expected %v", codes, exp)
+	}
+	for i := range exp {
+		if codes[i] != exp[i] {
+			t.Errorf("Encode A1 B gave %v, expected %v", codes, exp)
+			break
+		}
+	}
+
+	for _, test := range [...]string{"WE ARE DISCOVERED. FLEE AT ONCE", "ATTACK AT 12:00 (NOON)\r\n", "1+1=2?"} {
+		codes, err := Encode([]rune(test))
+		if err != nil {
+			t.Errorf("Encode %q failed: %v", test, err)
+		} else if text := string(Decode(codes)); text != test {
+			t.Errorf("Decode failed. Expected: %q\nActual: %q", test, text)
+		}
+	}
+
+	var irr *classical.InvalidRuneError
+	if _, err := Encode([]rune("ABc")); !errors.As(err, &irr) || irr.Position != 2 {
+		t.Errorf("Encode of a lowercase letter gave %v", err)
+	}
+
+	if s := Code(0b11000).String(); s != "xx..." {
+		t.Errorf("A is written %s, expected xx...", s)
+	}
+}
+
+func testTape(t *testing.T) {
+	codes := make([]Code, 32)
+	for i := range codes {
+		codes[i] = Code(i)
+	}
+
+	tape := FormatTape(codes)
+	if exp := "/T3O9HNM4LRGIPCVEZDBSYFXAWJ5UQK8"; string(tape) != exp {
+		t.Errorf("Tape failed. Expected: %s\nActual: %s", exp, string(tape))
+	}
+
+	parsed, err := ParseTape(tape)
+	if err != nil {
+		t.Fatalf("ParseTape failed: %v", err)
+	}
+	for i := range codes {
+		if parsed[i] != codes[i] {
+			t.Errorf("ParseTape gave %v for code %v", parsed[i], codes[i])
+		}
+	}
+
+	if _, err := ParseTape([]rune("AB7")); !errors.Is(err, classical.ErrInvalidText) {
+		t.Errorf("ParseTape of 7 gave %v", err)
+	}
+}
+
+func testWheels(t *testing.T) {
+	plain := []Code{0b11000, 0b11000, 0b11000}
+
+	if cipher, _ := EncryptCodes(dotKey(), plain); cipher[0] != plain[0] || cipher[2] != plain[2] {
+		t.Errorf("Dotted wheels changed the text to %v", cipher)
+	}
+
+	// A cross on chi 1 at the second position flips impulse 1 of the second character only.
+	k := dotKey()
+	k.Chi[0] = Wheel{Pattern: oneCross(ChiSizes[0], 2), Position: 1}
+	cipher, _ := EncryptCodes(k, plain)
+	if exp := []Code{0b11000, 0b01000, 0b11000}; cipher[0] != exp[0] || cipher[1] != exp[1] || cipher[2] != exp[2] {
+		t.Errorf("Chi 1 gave %v, expected %v", cipher, exp)
+	}
+
+	// The chi wheels wrap around after their length.
+	k.Chi[4] = Wheel{Pattern: oneCross(ChiSizes[4], 1), Position: 1}
+	cipher, _ = EncryptCodes(k, make([]Code, ChiSizes[4] + 1))
+	if cipher[0] != 0b00001 || cipher[ChiSizes[4]] != 0b00001 || cipher[ChiSizes[4] - 1] != 0 {
+		t.Errorf("Chi 5 did not wrap around: %v", cipher)
+	}
+}
+
+func testMotor(t *testing.T) {
+	// With mu 37 all dots the psi wheels stand still and psi 3 keeps adding impulse 3.
+	k := dotKey()
+	k.Psi[2] = Wheel{Pattern: oneCross(PsiSizes[2], 1), Position: 1}
+	cipher, _ := EncryptCodes(k, make([]Code, 4))
+	for i, c := range cipher {
+		if c != 0b00100 {
+			t.Errorf("Psi wheels moved at character %d: %v", i + 1, cipher)
+			break
+		}
+	}
+
+	// With mu 37 all crosses the psi wheels move at every character.
+	k.Mu[1] = Wheel{Pattern: strings.Repeat("x", MuSizes[1]), Position: 1}
+	cipher, _ = EncryptCodes(k, make([]Code, 3))
+	if cipher[0] != 0b00100 || cipher[1] != 0 || cipher[2] != 0 {
+		t.Errorf("Psi wheels did not move: %v", cipher)
+	}
+
+	// Mu 37 moves only when mu 61 shows a cross, a single cross on mu 37 then moves the psi
+	// wheels once, after the character read at the cross of mu 61.
+	k.Mu[0] = Wheel{Pattern: oneCross(MuSizes[0], 2), Position: 1}
+	k.Mu[1] = Wheel{Pattern: oneCross(MuSizes[1], 2), Position: 1}
+	cipher, _ = EncryptCodes(k, make([]Code, 5))
+	if exp := []Code{0b00100, 0b00100, 0b00100, 0, 0}; cipher[2] != exp[2] || cipher[3] != exp[3] || cipher[4] != exp[4] {
+		t.Errorf("Motor gave %v, expected %v", cipher, exp)
+	}
+}
+
+func testLimitation(t *testing.T) {
+	// Mu 37 never moves the psi wheels, a dot limitation moves them at every character.
+	k := dotKey()
+	k.Limitation = LimitationChi2
+	k.Psi[2] = Wheel{Pattern: oneCross(PsiSizes[2], 1), Position: 1}
+	cipher, _ := EncryptCodes(k, make([]Code, 2))
+	if cipher[0] != 0b00100 || cipher[1] != 0 {
+		t.Errorf("Dot limitation did not move the psi wheels: %v", cipher)
+	}
+
+	// A cross on chi 2 holds them, it also adds impulse 2 to the key.
+	k.Chi[1] = Wheel{Pattern: strings.Repeat("x", ChiSizes[1]), Position: 1}
+	cipher, _ = EncryptCodes(k, make([]Code, 2))
+	if cipher[0] != 0b01100 || cipher[1] != 0b01100 {
+		t.Errorf("Cross limitation moved the psi wheels: %v", cipher)
+	}
+
+	// Psi 1 cancels chi 2 on the SZ42B.
+	k.Limitation = LimitationChi2Psi1
+	k.Psi[0] = Wheel{Pattern: strings.Repeat("x", PsiSizes[0]), Position: 1}
+	cipher, _ = EncryptCodes(k, make([]Code, 2))
+	if cipher[0] != 0b11100 || cipher[1] != 0b11000 {
+		t.Errorf("SZ42B limitation gave %v", cipher)
+	}
+
+	// P5 reads the plain text, so only the decryption with the same key gets it back.
+	for _, limitation := range [...]Limitation{LimitationNone, LimitationChi2, LimitationChi2Psi1} {
+		k := RandomKey(nil, limitation)
+		k.P5 = limitation != LimitationNone
+		plain, _ := Encode([]rune("WE ARE DISCOVERED. FLEE AT ONCE"))
+
+		cipher, err := EncryptCodes(k, plain)
+		if err != nil {
+			t.Fatalf("Encrypt with limitation %d failed: %v", limitation, err)
+		}
+
+		decrypted, _ := DecryptCodes(k, cipher)
+		if text := string(Decode(decrypted)); text != "WE ARE DISCOVERED. FLEE AT ONCE" {
+			t.Errorf("Limitation %d round trip gave %q", limitation, text)
+		}
+	}
+}
+
+func testAdapter(t *testing.T) {
+	var c classical.ICipherClassical = New([]rune("ATTACK AT 1200"), RandomKey(nil, LimitationChi2))
+	if !c.Verify() {
+		t.Fatalf("Verify failed: %v", c.GetErrors())
+	}
+
+	if err := c.EncryptE(); err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if _, err := ParseTape(c.GetText()); err != nil {
+		t.Errorf("Cipher text %s is not a tape: %v", string(c.GetText()), err)
+	}
+
+	if err := c.DecryptE(); err != nil {
+		t.Fatalf("Decrypt failed: %v", err)
+	}
+	if text := string(c.GetText()); text != "ATTACK AT 1200" {
+		t.Errorf("Round trip failed. Expected: ATTACK AT 1200\nActual: %s", text)
+	}
+
+	// The chi 1 cross turns A into a line feed, written 4 on the tape.
+	k := dotKey()
+	k.Chi[0] = Wheel{Pattern: strings.Repeat("x", ChiSizes[0]), Position: 1}
+	if text, err := Encrypt(k, []rune("AAA")); err != nil || string(text) != "444" {
+		t.Errorf("Encrypt gave %s, %v, expected 444", string(text), err)
+	}
+	if text, err := Decrypt(k, []rune("444")); err != nil || string(text) != "AAA" {
+		t.Errorf("Decrypt gave %s, %v, expected AAA", string(text), err)
+	}
+
+	// Spaces are written 9 on the tape.
+	c = New([]rune("A A"), k)
+	if err := c.DecryptE(); !errors.Is(err, classical.ErrInvalidText) || string(c.GetText()) != "A A" || len(c.GetErrors()) > 0 {
+		t.Errorf("Decrypt of a plain text gave %v", err)
+	}
+}
+
+func testVerify(t *testing.T) {
+	invalids := []*Key{nil, {}}
+
+	k := dotKey()
+	k.Chi[0].Pattern = k.Chi[0].Pattern[1:]
+	invalids = append(invalids, k)
+
+	k = dotKey()
+	k.Psi[4].Pattern = strings.Repeat("1", PsiSizes[4])
+	invalids = append(invalids, k)
+
+	k = dotKey()
+	k.Mu[0].Position = MuSizes[0] + 1
+	invalids = append(invalids, k)
+
+	k = dotKey()
+	k.Mu[1].Position = 0
+	invalids = append(invalids, k)
+
+	k = dotKey()
+	k.P5 = true
+	invalids = append(invalids, k)
+
+	k = dotKey()
+	k.Limitation = Limitation(3)
+	invalids = append(invalids, k)
+
+	for i, k := range invalids {
+		c := New([]rune("A"), k)
+		if c.Verify() {
+			t.Errorf("Invalid key %d was verified", i)
+		}
+		if errs := len(c.GetErrors()); c.Verify() || len(c.GetErrors()) != errs {
+			t.Errorf("Invalid key %d kept the errors of the previous verification: %v", i, c.GetErrors())
+		}
+		if err := c.EncryptE(); !errors.Is(err, classical.ErrInvalidKey) {
+			t.Errorf("Invalid key %d encrypted: %v", i, err)
+		}
+	}
+
+	if k := RandomKey(nil, LimitationChi2Psi1); len(verifyKey(k)) > 0 {
+		t.Errorf("Random key is invalid: %v", verifyKey(k))
+	}
+}
@@ -3,7 +3,11 @@ package utils
 import "math/rand"
 
 func Shuffle[T comparable](col []T) []T {
-	rand.Shuffle(len(col), func(i, j int) {
+	return ShuffleRand(nil, col)
+}
+
+func ShuffleRand[T comparable](r *rand.Rand, col []T) []T {
+	RandOrDefault(r).Shuffle(len(col), func(i, j int) {
 		col[i], col[j] = col[j], col[i]
 	})
 
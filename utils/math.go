@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"errors"
+	"fmt"
 	"math"
 	"math/bits"
 
@@ -93,11 +95,96 @@ func Coprimes(n int) []int {
 	return sn
 }
 
-func ModInverseMatrix(ma *mat.Dense, m int) {
-	det := math.Round(mat.Det(ma))
-	idet := ModInverse(Mod(int(det), m), m)
-	ma.Inverse(ma)
-	ma.Apply(func(i, j int, v float64) float64 {
-		return float64(Mod(idet * int(math.Round(v * det)), m))
-	}, ma)
-}
\ No newline at end of file
+var ErrNotInvertible = errors.New("matrix is not invertible")
+
+func modMatrix(ma *mat.Dense, m int) [][]int {
+	r, c := ma.Dims()
+	rows := make([][]int, r)
+
+	for i := range rows {
+		rows[i] = make([]int, c)
+		for j := range rows[i] {
+			rows[i][j] = Mod(int(math.Round(ma.At(i, j))), m)
+		}
+	}
+
+	return rows
+}
+
+// Reduces the rows to upper triangular form with Euclid's algorithm on each column, which
+// only uses invertible row operations and therefore works for composite moduli. The returned
+// sign tracks the row swaps.
+func modTriangulate(rows [][]int, n, m int) int {
+	sign := 1
+
+	for c := 0; c < n; c++ {
+		for r := c + 1; r < len(rows); r++ {
+			for rows[r][c] != 0 {
+				q := rows[c][c] / rows[r][c]
+				for j := range rows[c] {
+					rows[c][j] = Mod(rows[c][j] - q * rows[r][j], m)
+				}
+				rows[c], rows[r] = rows[r], rows[c]
+				sign = -sign
+			}
+		}
+	}
+
+	return sign
+}
+
+func ModDeterminant(ma *mat.Dense, m int) int {
+	r, _ := ma.Dims()
+	rows := modMatrix(ma, m)
+	det := modTriangulate(rows, r, m)
+
+	for i := 0; i < r; i++ {
+		det = Mod(det * rows[i][i], m)
+	}
+
+	return det
+}
+
+func ModInverseMatrix(ma *mat.Dense, m int) (*mat.Dense, error) {
+	n, c := ma.Dims()
+	if n != c {
+		return nil, fmt.Errorf("%w: %dx%d is not square", ErrNotInvertible, n, c)
+	}
+
+	rows := modMatrix(ma, m)
+	for i := range rows {
+		identity := make([]int, n)
+		identity[i] = 1
+		rows[i] = append(rows[i], identity...)
+	}
+
+	modTriangulate(rows, n, m)
+
+	for c := 0; c < n; c++ {
+		if !IsCoprime(uint(rows[c][c]), uint(m)) {
+			return nil, fmt.Errorf("%w: determinant is not coprime with %d", ErrNotInvertible, m)
+		}
+
+		inv := ModInverse(rows[c][c], m)
+		for j := range rows[c] {
+			rows[c][j] = rows[c][j] * inv % m
+		}
+
+		for r := range rows {
+			if f := rows[r][c]; r != c && f != 0 {
+				for j := range rows[r] {
+					rows[r][j] = Mod(rows[r][j] - f * rows[c][j], m)
+				}
+			}
+		}
+	}
+
+	result := mat.NewDense(n, n, nil)
+	for i := range rows {
+		for j := 0; j < n; j++ {
+			result.Set(i, j, float64(rows[i][n + j]))
+		}
+	}
+
+	return result, nil
+}
@@ -6,6 +6,39 @@ import (
 	math_rand "math/rand"
 )
 
+type cryptoSource struct{}
+
+func (cryptoSource) Seed(seed int64) {}
+func (s cryptoSource) Int63() int64 { return int64(s.Uint64() & (1 << 63 - 1)) }
+func (cryptoSource) Uint64() uint64 {
+	var b [8]byte
+
+	if _, err := crypto_rand.Read(b[:]); err != nil {
+		panic("Cannot read from the cryptographically secure random number generator")
+	}
+
+	return binary.LittleEndian.Uint64(b[:])
+}
+
+// The crypto source holds no state, so the returned generator is safe for concurrent use.
+func NewCryptoRand() *math_rand.Rand {
+	return math_rand.New(cryptoSource{})
+}
+
+func NewSeededRand(seed int64) *math_rand.Rand {
+	return math_rand.New(math_rand.NewSource(seed))
+}
+
+var cryptoRand = NewCryptoRand()
+
+func RandOrDefault(r *math_rand.Rand) *math_rand.Rand {
+	if r == nil {
+		return cryptoRand
+	}
+
+	return r
+}
+
 // https://stackoverflow.com/questions/12321133/how-to-properly-seed-random-number-generator
 func SeedRand() int64 {
     var b [8]byte
@@ -19,4 +52,4 @@ func SeedRand() int64 {
     math_rand.Seed(seed)
 
     return seed
-}
\ No newline at end of file
+}